@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/codecrafters-io/cli/internal/actions"
+	"github.com/codecrafters-io/cli/internal/client"
 	"github.com/codecrafters-io/cli/internal/commands"
+	"github.com/codecrafters-io/cli/internal/config"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/fatih/color"
 )
@@ -21,20 +32,34 @@ func main() {
 		fmt.Fprintf(os.Stderr, `CLI to interact with CodeCrafters
 
 USAGE
-  $ codecrafters [command]
+  $ codecrafters [-C <dir>] [--remote <name>] [--server <name>] [command]
 
 EXAMPLES
   $ codecrafters submit            # Commit changes & run tests
   $ codecrafters submit -m "msg"   # Commit changes & run tests with a custom commit message
   $ codecrafters test              # Run tests without committing changes
   $ codecrafters test --previous   # Run tests for all previous stages and the current stage without committing changes
+  $ codecrafters test --watch      # Re-run tests whenever a file changes
+  $ codecrafters test --stage -1   # Run tests for the previous stage only
+  $ codecrafters test --dry-run    # Show the changes that would be pushed, without pushing them
+  $ codecrafters attach            # Resume streaming logs after an interruption
 
 COMMANDS
   submit:           Commit changes & run tests
   test:             Run tests without committing changes
   task:             View current stage instructions
+  stages:           List all stages and your progress
+  ui:               Browse stages and run tests in a full-screen interface
   update-buildpack: Update language version
+  buildpack:        List language versions, or switch to a specific one
+  status:           Show the state of this repository
+  history:          List past test runs & submissions
+  attach:           Resume streaming logs of an interrupted test run or submission
   ping:             Test the connection to a CodeCrafters repository
+  doctor:           Diagnose common problems with your setup
+  config:           View and change CLI settings
+  upgrade:          Upgrade to the latest version of the CLI
+  completion:       Print a shell completion script (bash, zsh or fish)
   help:             Show usage instructions
 
 VERSION
@@ -45,6 +70,13 @@ VERSION
 
 	help := flag.Bool("help", false, "show usage instructions")
 	showVersion := flag.Bool("version", false, "print version and exit")
+	outputFormat := flag.String("output", output.FormatText, "output format (text or json)")
+
+	var repoDir string
+	flag.StringVar(&repoDir, "C", "", "use the repository at this directory instead of the current one")
+	flag.StringVar(&repoDir, "repo-dir", "", "use the repository at this directory instead of the current one")
+	remoteName := flag.String("remote", "", "git remote to use when there are several CodeCrafters remotes")
+	serverName := flag.String("server", "", "CodeCrafters server to use: a name from the servers section of the config, or a URL")
 	flag.Parse()
 
 	if *help {
@@ -57,39 +89,128 @@ VERSION
 		os.Exit(0)
 	}
 
-	err := run()
+	err := setOutputFormat(*outputFormat)
+	if err == nil && repoDir != "" {
+		err = utils.SetRepositoryDir(repoDir)
+	}
+
+	if *remoteName != "" {
+		// Through the environment (like the remote setting), so that test runs started by `test --watch` and `ui` use it too
+		os.Setenv(config.EnvVarName("remote"), *remoteName)
+	}
+
+	if err == nil {
+		err = loadConfig()
+	}
+
+	if err == nil && *serverName != "" {
+		err = setServer(*serverName)
+	}
+
+	// Ctrl-C (or SIGTERM) cancels ctx instead of killing the process, so that commands can stop what they're doing and
+	// clean up (e.g. remove temp directories) before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go exitOnInterrupt(ctx, stop)
+
+	if err == nil {
+		err = run(ctx)
+	}
+
+	if err != nil && ctx.Err() != nil {
+		exitInterrupted()
+	}
+
+	var exitErr actions.ExitError
+	if errors.As(err, &exitErr) {
+		// The server decided the exit code (e.g. because tests failed), and has already printed why
+		output.EmitResult(exitErr.ExitCode, nil)
+		os.Exit(exitErr.ExitCode)
+	}
+
 	if err != nil {
+		output.EmitResult(1, err)
+
 		red := color.New(color.FgRed).SprintFunc()
 
 		if err.Error() != "" {
 			fmt.Fprintf(os.Stderr, "%v\n", red(err))
 		}
 
+		if hint := apiErrorHint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "%s\n", hint)
+		}
+
 		os.Exit(1)
 	}
 
+	output.EmitResult(0, nil)
 	os.Exit(0)
 }
 
-func run() error {
+func run(ctx context.Context) error {
 	cmd := flag.Arg(0)
 	utils.Logger.Debug().Msgf("Running command: %s", cmd)
 
+	// When adding a command or flag, also add it to completionCommands in internal/commands/completion.go
 	switch cmd {
 	case "test":
 		testCmd := flag.NewFlagSet("test", flag.ExitOnError)
 		shouldTestPrevious := testCmd.Bool("previous", false, "Run tests for all previous stages and the current stage without committing changes")
+		shouldWatch := testCmd.Bool("watch", false, "Re-run tests whenever a file in the repository changes")
+		stage := testCmd.String("stage", "", "Run tests for a specific stage (slug, +N, or -N)")
+		stageRange := testCmd.String("stages", "", "Run tests for a range of stages (e.g. 3..7, -3..-1 or slug..slug)")
+		isLocal := testCmd.Bool("local", false, "Run tests locally using the tester passed in --tester, without pushing changes")
+		testerPath := testCmd.String("tester", "", "Path to a locally installed tester executable (used with --local)")
+		isDryRun := testCmd.Bool("dry-run", false, "Show the changes that would be pushed, without pushing them")
 		testCmd.Parse(flag.Args()[1:]) // parse the args after the test command
 
-		return commands.TestCommand(*shouldTestPrevious)
+		selectedStageFlagCount := 0
+		for _, isSet := range []bool{*shouldTestPrevious, *stage != "", *stageRange != ""} {
+			if isSet {
+				selectedStageFlagCount++
+			}
+		}
+
+		if selectedStageFlagCount > 1 {
+			return fmt.Errorf("Only one of --previous, --stage or --stages can be used at a time.")
+		}
+
+		if *isLocal && *testerPath == "" {
+			return fmt.Errorf("Use --tester to set the path of the tester to run with --local.")
+		}
+
+		if !*isLocal && *testerPath != "" {
+			return fmt.Errorf("--tester can only be used with --local.")
+		}
+
+		if *isDryRun && (*isLocal || *shouldWatch) {
+			return fmt.Errorf("--dry-run can't be combined with --local or --watch.")
+		}
+
+		if *shouldWatch && output.IsJSON() {
+			return fmt.Errorf("--watch can't be combined with --output json.")
+		}
+
+		if *shouldWatch {
+			return commands.TestWatchCommand(ctx, argsWithoutFlag(flag.Args()[1:], "watch"))
+		}
+
+		if *isLocal {
+			return commands.TestLocalCommand(ctx, *testerPath, *shouldTestPrevious, *stage, *stageRange)
+		}
+
+		return commands.TestCommand(ctx, *shouldTestPrevious, *stage, *stageRange, *isDryRun)
 	case "submit":
 		submitCmd := flag.NewFlagSet("submit", flag.ExitOnError)
 
 		var commitMessage string
-		defaultCommitMessage := "codecrafters submit"
+		defaultCommitMessage := config.Get("submit.commit_message")
 		usage := "Commit changes & run tests with a custom commit message"
 		submitCmd.StringVar(&commitMessage, "m", defaultCommitMessage, usage)
 		submitCmd.StringVar(&commitMessage, "message", defaultCommitMessage, usage)
+		isDryRun := submitCmd.Bool("dry-run", false, "Show the changes that would be committed, without committing them")
 
 		submitCmd.Parse(flag.Args()[1:])
 		if submitCmd.NArg() > 0 {
@@ -99,18 +220,121 @@ func run() error {
 			return fmt.Errorf("Cannot submit with an empty commit message.")
 		}
 
-		return commands.SubmitCommand(commitMessage + " [skip ci]")
+		return commands.SubmitCommand(ctx, commitMessage+" [skip ci]", *isDryRun)
 	case "task":
 		taskCmd := flag.NewFlagSet("task", flag.ExitOnError)
 		stageSlug := taskCmd.String("stage", "", "view instructions for a specific stage (slug, +N, or -N)")
 		raw := taskCmd.Bool("raw", false, "print instructions without pretty-printing")
+		exportDir := taskCmd.String("export", "", "write instructions for all stages to this directory")
+		exportFormat := taskCmd.String("format", "md", "format of exported instructions (md or html)")
 		taskCmd.Parse(flag.Args()[1:])
 
-		return commands.TaskCommand(*stageSlug, *raw)
+		if *exportDir != "" {
+			if *stageSlug != "" || *raw {
+				return fmt.Errorf("--export can't be combined with --stage or --raw.")
+			}
+
+			return commands.TaskExportCommand(ctx, *exportDir, *exportFormat)
+		}
+
+		return commands.TaskCommand(ctx, *stageSlug, *raw)
+	case "stages":
+		stagesCmd := flag.NewFlagSet("stages", flag.ExitOnError)
+		format := stagesCmd.String("format", "table", "output format (table, plain or json)")
+		stagesCmd.Parse(flag.Args()[1:])
+
+		return commands.StagesCommand(ctx, *format)
+	case "ui":
+		return commands.UICommand(ctx)
 	case "update-buildpack":
-		return commands.UpdateBuildpackCommand()
+		updateBuildpackCmd := flag.NewFlagSet("update-buildpack", flag.ExitOnError)
+		assumeYes := updateBuildpackCmd.Bool("yes", false, "upgrade without asking for confirmation")
+		updateBuildpackCmd.Parse(flag.Args()[1:])
+
+		return commands.UpdateBuildpackCommand(ctx, *assumeYes)
+	case "buildpack":
+		buildpackCmd := flag.NewFlagSet("buildpack", flag.ExitOnError)
+		assumeYes := buildpackCmd.Bool("yes", false, "switch without asking for confirmation")
+
+		// Usage: codecrafters buildpack set [--yes] <slug>
+		if flag.NArg() > 1 {
+			buildpackCmd.Parse(flag.Args()[2:])
+		}
+
+		switch {
+		case flag.Arg(1) == "list" && buildpackCmd.NArg() == 0:
+			return commands.BuildpackListCommand(ctx)
+		case flag.Arg(1) == "set" && buildpackCmd.NArg() == 1:
+			return commands.BuildpackSetCommand(ctx, buildpackCmd.Arg(0), *assumeYes)
+		default:
+			return fmt.Errorf("Usage: codecrafters buildpack list\n       codecrafters buildpack set [--yes] <slug>")
+		}
+	case "status":
+		statusCmd := flag.NewFlagSet("status", flag.ExitOnError)
+		asJson := statusCmd.Bool("json", false, "print status as JSON")
+		statusCmd.Parse(flag.Args()[1:])
+
+		return commands.StatusCommand(ctx, *asJson)
+	case "history":
+		historyCmd := flag.NewFlagSet("history", flag.ExitOnError)
+		commandFilter := historyCmd.String("command", "", "only show submissions created by this command (test or submit)")
+		statusFilter := historyCmd.String("status", "", "only show submissions with this status (success, failure or evaluating)")
+		limit := historyCmd.Int("limit", 20, "maximum number of submissions to show (0 for all)")
+		asJson := historyCmd.Bool("json", false, "print history as JSON")
+
+		// Usage: codecrafters history show <submission-id>
+		if flag.Arg(1) == "show" {
+			historyCmd.Parse(flag.Args()[2:])
+			if historyCmd.NArg() != 1 {
+				return fmt.Errorf("Usage: codecrafters history show [--json] <submission-id>")
+			}
+
+			return commands.HistoryShowCommand(historyCmd.Arg(0), *asJson)
+		}
+
+		historyCmd.Parse(flag.Args()[1:])
+
+		return commands.HistoryCommand(*commandFilter, *statusFilter, *limit, *asJson)
+	case "attach":
+		attachCmd := flag.NewFlagSet("attach", flag.ExitOnError)
+		attachCmd.Parse(flag.Args()[1:])
+
+		if attachCmd.NArg() > 1 {
+			return fmt.Errorf("Usage: codecrafters attach [submission-id]")
+		}
+
+		// Defaults to the most recent submission
+		return commands.AttachCommand(ctx, attachCmd.Arg(0))
 	case "ping":
-		return commands.PingCommand()
+		return commands.PingCommand(ctx)
+	case "doctor":
+		return commands.DoctorCommand(ctx)
+	case "config":
+		configCmd := flag.NewFlagSet("config", flag.ExitOnError)
+		isLocal := configCmd.Bool("local", false, "change the current repository's settings instead of the global ones")
+
+		// Usage: codecrafters config <list|get|set|unset> [--local] [args...]
+		if flag.NArg() < 2 {
+			return commands.ConfigCommand("", nil, false)
+		}
+
+		configCmd.Parse(flag.Args()[2:])
+
+		return commands.ConfigCommand(flag.Arg(1), configCmd.Args(), *isLocal)
+	case "upgrade":
+		upgradeCmd := flag.NewFlagSet("upgrade", flag.ExitOnError)
+		assumeYes := upgradeCmd.Bool("yes", false, "upgrade without asking for confirmation")
+		upgradeCmd.Parse(flag.Args()[1:])
+
+		return commands.UpgradeCommand(ctx, *assumeYes)
+	case "completion":
+		if flag.NArg() != 2 {
+			return fmt.Errorf("Usage: codecrafters completion <bash|zsh|fish>")
+		}
+
+		return commands.CompletionCommand(flag.Arg(1))
+	case "__complete-stages": // used by completion scripts
+		return commands.CompleteStagesCommand()
 	case "help",
 		"": // no argument
 		flag.Usage()
@@ -125,6 +349,142 @@ func run() error {
 	return nil
 }
 
+// interruptGracePeriod is how long a command has to return once interrupted. Commands blocked on something that can't
+// be cancelled (like waiting for the answer to a prompt) are exited without cleaning up after that.
+const interruptGracePeriod = 3 * time.Second
+
+// exitOnInterrupt exits if the command doesn't return within interruptGracePeriod of being interrupted. Interrupting
+// again exits right away.
+func exitOnInterrupt(ctx context.Context, stop context.CancelFunc) {
+	<-ctx.Done()
+
+	// Restores the default behavior, i.e. the next Ctrl-C kills the process
+	stop()
+
+	time.Sleep(interruptGracePeriod)
+	exitInterrupted()
+}
+
+// exitInterrupted exits with the conventional exit code for SIGINT, leaving the terminal in a sane state
+func exitInterrupted() {
+	if !color.NoColor && !output.IsJSON() {
+		// The tester's output might have been cut off in the middle of a colored line
+		fmt.Print("\033[0m")
+	}
+
+	output.EmitResult(130, fmt.Errorf("Interrupted."))
+
+	red := color.New(color.FgRed).SprintFunc()
+	fmt.Fprintf(os.Stderr, "\n%v\n", red("Interrupted."))
+
+	os.Exit(130)
+}
+
+// apiErrorHint returns what users can do about a failed request to the CodeCrafters API, or "" if err isn't one
+func apiErrorHint(err error) string {
+	var apiErr client.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+
+	switch {
+	case apiErr.StatusCode == 0 && apiErr.Retryable:
+		return "Couldn't reach CodeCrafters. Check your internet connection, or run `codecrafters doctor` to diagnose the problem."
+	case apiErr.Retryable:
+		return "CodeCrafters is having temporary issues. Please try again in a few minutes."
+	case apiErr.StatusCode >= 500 && apiErr.RequestId != "":
+		return fmt.Sprintf("If this keeps happening, contact hello@codecrafters.io and mention request id %s.", apiErr.RequestId)
+	default:
+		return ""
+	}
+}
+
+// commandsWithJSONOutput are the commands that report through internal/output, and so support `--output json`
+var commandsWithJSONOutput = []string{"test", "submit", "task", "ping", "update-buildpack"}
+
+func setOutputFormat(format string) error {
+	if err := output.SetFormat(format); err != nil {
+		return err
+	}
+
+	if !output.IsJSON() {
+		return nil
+	}
+
+	if !slices.Contains(commandsWithJSONOutput, flag.Arg(0)) {
+		// Reset, so that the error isn't reported as a JSON event for a command that doesn't support them
+		output.SetFormat(output.FormatText)
+
+		return fmt.Errorf("--output json is only supported by %s.", strings.Join(commandsWithJSONOutput, ", "))
+	}
+
+	return nil
+}
+
+// loadConfig loads the global and repository config files and applies the settings that affect all commands
+func loadConfig() error {
+	// Settings can be changed outside of a repository too, in which case only the global config file is used
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		repoDir = ""
+	}
+
+	if err := config.Load(repoDir); err != nil {
+		if flag.Arg(0) != "config" {
+			return err
+		}
+
+		// `config` is how a broken config file gets fixed, so it has to keep working. Changing a setting in the broken
+		// file replaces it, see Config.Set.
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Fprintf(os.Stderr, "%s\n\n", yellow(fmt.Sprintf("Warning: %s\nUntil this is fixed, the invalid parts of the config are ignored.", err)))
+	}
+
+	utils.SetLogLevel(config.Get("log_level"))
+	utils.SetPreferredRemoteName(config.Get("remote"))
+	utils.SetGitHostServerURLs(config.GitHosts())
+
+	if err := client.SetupHTTPClient(); err != nil {
+		return err
+	}
+
+	switch config.Get("color") {
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	}
+
+	return nil
+}
+
+// setServer makes commands use the server passed to --server instead of the one inferred from the git remote
+func setServer(name string) error {
+	serverUrl, err := config.ResolveServer(name)
+	if err != nil {
+		return err
+	}
+
+	// Like --remote, through the environment so that test runs started by `test --watch` and `ui` use it too
+	return os.Setenv(config.EnvVarName("server_url"), serverUrl)
+}
+
+// argsWithoutFlag removes a boolean flag (in any of its -name, --name or --name=value forms) from args
+func argsWithoutFlag(args []string, name string) []string {
+	filteredArgs := []string{}
+
+	for _, arg := range args {
+		flagName, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && flagName == name {
+			continue
+		}
+
+		filteredArgs = append(filteredArgs, arg)
+	}
+
+	return filteredArgs
+}
+
 func envOr(name, defaultVal string) string {
 	v, ok := os.LookupEnv(name)
 	if ok {
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runMainEnvVar makes the test binary run the CLI instead of the tests, so that tests can check what it writes to
+// stdout and stderr
+const runMainEnvVar = "CODECRAFTERS_TEST_RUN_MAIN"
+
+// printGlobalFlagsEnvVar makes `-h` list the global flags like it does for commands, instead of showing the usage
+// instructions
+const printGlobalFlagsEnvVar = "CODECRAFTERS_TEST_PRINT_GLOBAL_FLAGS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnvVar) == "1" {
+		if os.Getenv(printGlobalFlagsEnvVar) == "1" {
+			// main replaces flag.Usage, which only the default FlagSet calls
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+			flag.CommandLine.Usage = flag.CommandLine.PrintDefaults
+		}
+
+		main()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+// runCLI runs the CLI with args in repoDir, and returns its stdout and stderr
+func runCLI(t *testing.T, repoDir string, env []string, args ...string) (string, string, error) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(), append([]string{
+		runMainEnvVar + "=1",
+		"SENTRY_DSN=",
+		"HOME=" + t.TempDir(),
+		"XDG_CONFIG_HOME=" + t.TempDir(),
+		"XDG_DATA_HOME=" + t.TempDir(),
+		"XDG_CACHE_HOME=" + t.TempDir(),
+	}, env...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	return stdout.String(), stderr.String(), err
+}
+
+func setupRepository(t *testing.T) string {
+	repoDir := t.TempDir()
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", "https://git.codecrafters.io/abc123"},
+	} {
+		output, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "codecrafters.yml"), []byte("buildpack: go-1.21\n"), 0644))
+
+	return repoDir
+}
+
+func TestUpdateBuildpackWithJSONOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/cli/fetch_repository_buildpack":
+			io.WriteString(w, `{"buildpack": {"slug": "go-1.21"}}`)
+		case "/services/cli/fetch_buildpacks":
+			io.WriteString(w, `{"buildpacks": [{"slug": "go-1.21"}, {"slug": "go-1.22", "is_latest": true}]}`)
+		case "/services/cli/update_buildpack":
+			io.WriteString(w, `{"buildpack": {"slug": "go-1.22"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoDir := setupRepository(t)
+
+	stdout, stderr, err := runCLI(t, repoDir, []string{"CODECRAFTERS_SERVER_URL=" + server.URL}, "--output", "json", "update-buildpack", "--yes")
+	require.NoError(t, err, "stderr: %s", stderr)
+
+	// The assumed answer to the confirmation prompt isn't part of the JSON output
+	assert.Contains(t, stderr, "Do you want to upgrade to go-1.22? [y/N] y (assumed)")
+
+	events := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "stdout line isn't JSON: %s", line)
+
+		events = append(events, event)
+	}
+
+	eventTypes := []interface{}{}
+	for _, event := range events {
+		eventTypes = append(eventTypes, event["type"])
+	}
+
+	assert.Contains(t, eventTypes, "buildpack_updated")
+	assert.Equal(t, "result", events[len(events)-1]["type"])
+	assert.Equal(t, true, events[len(events)-1]["success"])
+
+	codecraftersYml, err := os.ReadFile(filepath.Join(repoDir, "codecrafters.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "buildpack: go-1.22\n", string(codecraftersYml))
+}
+
+// parseFlagDefaults parses the flags listed by flag.PrintDefaults, and whether they take a value
+func parseFlagDefaults(text string) map[string]bool {
+	flags := map[string]bool{}
+
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, "  -") {
+			continue
+		}
+
+		// e.g. "  -stage string" or "  -m string\tusage"
+		nameAndType, _, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		fields := strings.Fields(nameAndType)
+		flags[strings.TrimPrefix(fields[0], "-")] = len(fields) > 1
+	}
+
+	return flags
+}
+
+var (
+	fishCommandRegexp = regexp.MustCompile(`^complete -c codecrafters -n 'not __codecrafters_command' -a (\S+) -d`)
+	fishFlagRegexp    = regexp.MustCompile(`^complete -c codecrafters -n '(?:not __codecrafters_command|__codecrafters_using_command (\S+))' -[ls] (\S+)(.*?) -d '`)
+)
+
+// parseFishCompletion returns the commands the fish completion script completes, and the flags it completes for each
+// of them (the global flags under ""), with whether they take a value
+func parseFishCompletion(script string) ([]string, map[string]map[string]bool) {
+	commands := []string{}
+	flags := map[string]map[string]bool{"": {}}
+
+	for _, line := range strings.Split(script, "\n") {
+		if match := fishCommandRegexp.FindStringSubmatch(line); match != nil {
+			commands = append(commands, match[1])
+			flags[match[1]] = map[string]bool{}
+		}
+
+		if match := fishFlagRegexp.FindStringSubmatch(line); match != nil {
+			flags[match[1]][match[2]] = strings.Contains(match[3], " -x") || strings.Contains(match[3], " -r")
+		}
+	}
+
+	return commands, flags
+}
+
+// TestCompletionMatchesFlags checks that completion scripts know about the commands and flags main parses
+func TestCompletionMatchesFlags(t *testing.T) {
+	workingDir := t.TempDir()
+
+	fishScript, stderr, err := runCLI(t, workingDir, nil, "completion", "fish")
+	require.NoError(t, err, stderr)
+
+	completedCommands, completedFlags := parseFishCompletion(fishScript)
+
+	_, usage, err := runCLI(t, workingDir, nil, "help")
+	require.NoError(t, err)
+
+	commands := []string{}
+	for _, match := range regexp.MustCompile(`(?m)^  ([a-z-]+):\s`).FindAllStringSubmatch(usage, -1) {
+		commands = append(commands, match[1])
+	}
+
+	assert.Equal(t, commands, completedCommands)
+
+	_, globalFlagDefaults, err := runCLI(t, workingDir, []string{printGlobalFlagsEnvVar + "=1"}, "-h")
+	require.NoError(t, err)
+	assert.Equal(t, parseFlagDefaults(globalFlagDefaults), completedFlags[""], "global flags")
+
+	// How to get each command with flags to list them
+	flagHelpArgs := map[string][]string{
+		"submit":           {"submit", "-h"},
+		"test":             {"test", "-h"},
+		"task":             {"task", "-h"},
+		"stages":           {"stages", "-h"},
+		"update-buildpack": {"update-buildpack", "-h"},
+		"buildpack":        {"buildpack", "list", "-h"},
+		"status":           {"status", "-h"},
+		"history":          {"history", "-h"},
+		"attach":           {"attach", "-h"},
+		"config":           {"config", "list", "-h"},
+		"upgrade":          {"upgrade", "-h"},
+	}
+
+	for _, command := range commands {
+		args, hasFlags := flagHelpArgs[command]
+		if !hasFlags {
+			assert.Empty(t, completedFlags[command], "flags of %s", command)
+			continue
+		}
+
+		_, flagDefaults, err := runCLI(t, workingDir, nil, args...)
+		require.NoError(t, err, flagDefaults)
+		assert.Equal(t, parseFlagDefaults(flagDefaults), completedFlags[command], "flags of %s", command)
+	}
+}
@@ -7,13 +7,10 @@ import (
 	"github.com/codecrafters-io/cli/internal/client"
 )
 
+// Action is a step of a test run or submission, as instructed by the server. Actions that wait (for a response, or
+// just for time to pass) return early with ctx's error once ctx is cancelled.
 type Action interface {
-	Execute() error
-}
-
-type InterruptibleAction interface {
-	Action
-	ExecuteWithContext(ctx context.Context) error
+	Execute(ctx context.Context) error
 }
 
 func ActionFromDefinition(actionDefinition client.ActionDefinition) (Action, error) {
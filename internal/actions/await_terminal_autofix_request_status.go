@@ -3,10 +3,12 @@ package actions
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/getsentry/sentry-go"
 )
 
@@ -68,17 +70,21 @@ func NewAwaitTerminalAutofixRequestStatusAction(argsJson json.RawMessage) (*Awai
 	}, nil
 }
 
-func (a *AwaitTerminalAutofixRequestStatusAction) Execute() error {
+// errInProgressActionsFinished is the cause of the cancellation of in-progress actions once the autofix request reaches
+// a terminal status
+var errInProgressActionsFinished = errors.New("in-progress actions finished")
+
+func (a *AwaitTerminalAutofixRequestStatusAction) Execute(ctx context.Context) error {
 	attempts := 0
 	autofixRequestStatus := "in_progress"
 
 	inProgressActionsDoneCh := make(chan bool)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	inProgressCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(errInProgressActionsFinished)
 
 	go func() {
-		if err := a.executeInProgressActions(ctx); err != nil {
+		if err := a.executeInProgressActions(inProgressCtx); err != nil && ctx.Err() == nil {
 			sentry.CaptureException(err)
 		}
 
@@ -86,36 +92,44 @@ func (a *AwaitTerminalAutofixRequestStatusAction) Execute() error {
 	}()
 
 	// We wait for upto 60 seconds (+ the time it takes to fetch status each time)
-	for autofixRequestStatus == "in_progress" && attempts < 60 {
+	for autofixRequestStatus == "in_progress" && attempts < 60 && ctx.Err() == nil {
 		var err error
 
 		codecraftersClient := client.NewCodecraftersClient()
-		autofixRequestStatusResponse, err := codecraftersClient.FetchAutofixRequest(a.SubmissionID)
+		autofixRequestStatusResponse, err := codecraftersClient.FetchAutofixRequest(ctx, a.SubmissionID)
 		if err != nil {
 			// We can still proceed here anyway
-			sentry.CaptureException(err)
+			if ctx.Err() == nil {
+				sentry.CaptureException(err)
+			}
 		} else {
 			autofixRequestStatus = autofixRequestStatusResponse.Status
 		}
 
 		attempts += 1
-		time.Sleep(time.Second)
+		sleep(ctx, time.Second)
 	}
 
-	// Ensure interruptible actions (like printing progress bars) finish early
-	cancel()
+	// Ensure in-progress actions (like printing progress bars) finish early
+	cancel(errInProgressActionsFinished)
 	<-inProgressActionsDoneCh
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	output.Emit("autofix_request_status", map[string]interface{}{"submission_id": a.SubmissionID, "status": autofixRequestStatus})
+
 	switch autofixRequestStatus {
 	case "success":
 		for _, action := range a.OnSuccessActions {
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
 	case "failure":
 		for _, action := range a.OnFailureActions {
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
@@ -123,11 +137,11 @@ func (a *AwaitTerminalAutofixRequestStatusAction) Execute() error {
 		err := fmt.Errorf("unexpected autofix request status: %s", autofixRequestStatus)
 		sentry.CaptureException(err)
 
-		PrintMessageAction{Color: "red", Text: "We failed to analyze your test failure in time. Please try again?"}.Execute()
-		PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute()
+		PrintMessageAction{Color: "red", Text: "We failed to analyze your test failure in time. Please try again?"}.Execute(ctx)
+		PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute(ctx)
 
 		// This is an internal error, let's terminate
-		TerminateAction{ExitCode: 1}.Execute()
+		return TerminateAction{ExitCode: 1}.Execute(ctx)
 	}
 
 	return nil
@@ -135,14 +149,8 @@ func (a *AwaitTerminalAutofixRequestStatusAction) Execute() error {
 
 func (a *AwaitTerminalAutofixRequestStatusAction) executeInProgressActions(ctx context.Context) error {
 	for _, action := range a.InProgressActions {
-		if interruptibleAction, ok := action.(InterruptibleAction); ok {
-			if err := interruptibleAction.ExecuteWithContext(ctx); err != nil {
-				return err
-			}
-		} else {
-			if err := action.Execute(); err != nil {
-				return err
-			}
+		if err := action.Execute(ctx); err != nil {
+			return err
 		}
 	}
 
@@ -1,11 +1,13 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/getsentry/sentry-go"
 )
 
@@ -54,7 +56,7 @@ func NewAwaitTerminalBuildStatusAction(argsJson json.RawMessage) (*AwaitTerminal
 	}, nil
 }
 
-func (a *AwaitTerminalBuildStatusAction) Execute() error {
+func (a *AwaitTerminalBuildStatusAction) Execute(ctx context.Context) error {
 	attempts := 0
 	buildStatus := "not_started"
 
@@ -63,28 +65,34 @@ func (a *AwaitTerminalBuildStatusAction) Execute() error {
 		var err error
 
 		codecraftersClient := client.NewCodecraftersClient()
-		resp, err := codecraftersClient.FetchBuild(a.BuildID)
+		resp, err := codecraftersClient.FetchBuild(ctx, a.BuildID)
 		if err != nil {
 			// We can still proceed here anyway
-			sentry.CaptureException(err)
+			if ctx.Err() == nil {
+				sentry.CaptureException(err)
+			}
 		} else {
 			buildStatus = resp.Status
 		}
 
 		attempts += 1
-		time.Sleep(time.Duration(100*attempts) * time.Millisecond)
+		if err := sleep(ctx, time.Duration(100*attempts)*time.Millisecond); err != nil {
+			return err
+		}
 	}
 
+	output.Emit("build_status", map[string]interface{}{"build_id": a.BuildID, "status": buildStatus})
+
 	switch buildStatus {
 	case "success":
 		for _, action := range a.OnSuccessActions {
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
 	case "failure":
 		for _, action := range a.OnFailureActions {
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
@@ -92,17 +100,17 @@ func (a *AwaitTerminalBuildStatusAction) Execute() error {
 		err := fmt.Errorf("unexpected build status: %s", buildStatus)
 		sentry.CaptureException(err)
 
-		printErr := PrintMessageAction{Color: "red", Text: "We couldn't fetch the results of your build. Please try again?"}.Execute()
+		printErr := PrintMessageAction{Color: "red", Text: "We couldn't fetch the results of your build. Please try again?"}.Execute(ctx)
 		if printErr != nil {
 			return printErr
 		}
-		printErr = PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute()
+		printErr = PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute(ctx)
 		if printErr != nil {
 			return printErr
 		}
 
 		// If the build failed, we don't need to stream test logs
-		return TerminateAction{ExitCode: 1}.Execute()
+		return TerminateAction{ExitCode: 1}.Execute(ctx)
 	}
 
 	return nil
@@ -1,16 +1,26 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/getsentry/sentry-go"
 )
 
+// terminalSubmissionStatusHandler is called with the final status of a submission before any follow-up actions (which
+// might terminate the process) are executed.
+var terminalSubmissionStatusHandler func(submissionId string, status string)
+
+func SetTerminalSubmissionStatusHandler(handler func(submissionId string, status string)) {
+	terminalSubmissionStatusHandler = handler
+}
+
 type AwaitTerminalSubmissionStatusAction struct {
 	SubmissionID     string
 	OnSuccessActions []Action
@@ -56,7 +66,7 @@ func NewAwaitTerminalSubmissionStatusAction(argsJson json.RawMessage) (*AwaitTer
 	}, nil
 }
 
-func (a *AwaitTerminalSubmissionStatusAction) Execute() error {
+func (a *AwaitTerminalSubmissionStatusAction) Execute(ctx context.Context) error {
 	attempts := 0
 	submissionStatus := "evaluating"
 
@@ -64,16 +74,26 @@ func (a *AwaitTerminalSubmissionStatusAction) Execute() error {
 		var err error
 
 		codecraftersClient := client.NewCodecraftersClient()
-		resp, err := codecraftersClient.FetchSubmission(a.SubmissionID)
+		resp, err := codecraftersClient.FetchSubmission(ctx, a.SubmissionID)
 		if err != nil {
 			// We have retries, so we can proceed here anyway
-			sentry.CaptureException(err)
+			if ctx.Err() == nil {
+				sentry.CaptureException(err)
+			}
 		} else {
 			submissionStatus = resp.Status
 		}
 
 		attempts += 1
-		time.Sleep(time.Duration(100*attempts) * time.Millisecond)
+		if err := sleep(ctx, time.Duration(100*attempts)*time.Millisecond); err != nil {
+			return err
+		}
+	}
+
+	output.Emit("submission_status", map[string]interface{}{"submission_id": a.SubmissionID, "status": submissionStatus})
+
+	if terminalSubmissionStatusHandler != nil {
+		terminalSubmissionStatusHandler(a.SubmissionID, submissionStatus)
 	}
 
 	switch submissionStatus {
@@ -81,7 +101,7 @@ func (a *AwaitTerminalSubmissionStatusAction) Execute() error {
 		for _, action := range a.OnSuccessActions {
 			utils.Logger.Debug().Msgf("Executing on_success action: %s", reflect.TypeOf(action).String())
 
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
@@ -89,7 +109,7 @@ func (a *AwaitTerminalSubmissionStatusAction) Execute() error {
 		for _, action := range a.OnFailureActions {
 			utils.Logger.Debug().Msgf("Executing on_failure action: %s", reflect.TypeOf(action).String())
 
-			if err := action.Execute(); err != nil {
+			if err := action.Execute(ctx); err != nil {
 				return err
 			}
 		}
@@ -97,20 +117,20 @@ func (a *AwaitTerminalSubmissionStatusAction) Execute() error {
 		err := fmt.Errorf("unexpected submission status: %s", submissionStatus)
 		sentry.CaptureException(err)
 
-		printErr := PrintMessageAction{Color: "red", Text: "We couldn't fetch the results of your submission. Please try again?"}.Execute()
+		printErr := PrintMessageAction{Color: "red", Text: "We couldn't fetch the results of your submission. Please try again?"}.Execute(ctx)
 		if printErr != nil {
 			return printErr
 		}
-		printErr = PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute()
+		printErr = PrintMessageAction{Color: "red", Text: "Let us know at hello@codecrafters.io if this error persists."}.Execute(ctx)
 		if printErr != nil {
 			return printErr
 		}
-		printErr = PrintMessageAction{Color: "plain", Text: ""}.Execute()
+		printErr = PrintMessageAction{Color: "plain", Text: ""}.Execute(ctx)
 		if printErr != nil {
 			return printErr
 		}
 
-		return TerminateAction{ExitCode: 1}.Execute()
+		return TerminateAction{ExitCode: 1}.Execute(ctx)
 	}
 
 	return nil
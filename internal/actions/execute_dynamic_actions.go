@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/codecrafters-io/cli/internal/client"
@@ -20,9 +21,9 @@ func NewExecuteDynamicActionsAction(argsJson json.RawMessage) (ExecuteDynamicAct
 	return executeDynamicActionsAction, nil
 }
 
-func (a ExecuteDynamicActionsAction) Execute() error {
+func (a ExecuteDynamicActionsAction) Execute(ctx context.Context) error {
 	codecraftersClient := client.NewCodecraftersClient()
-	response, err := codecraftersClient.FetchDynamicActions(a.EventName, a.EventParams)
+	response, err := codecraftersClient.FetchDynamicActions(ctx, a.EventName, a.EventParams)
 	if err != nil {
 		return err
 	}
@@ -38,7 +39,7 @@ func (a ExecuteDynamicActionsAction) Execute() error {
 	}
 
 	for _, action := range actions {
-		if err := action.Execute(); err != nil {
+		if err := action.Execute(ctx); err != nil {
 			return err
 		}
 	}
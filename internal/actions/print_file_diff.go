@@ -1,12 +1,13 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/termenv"
+	"github.com/codecrafters-io/cli/internal/output"
 )
 
 type PrintFileDiffAction struct {
@@ -24,8 +25,13 @@ func NewPrintFileDiffAction(argsJson json.RawMessage) (PrintFileDiffAction, erro
 }
 
 // TODO: Handle printing chunks!
-func (a PrintFileDiffAction) Execute() error {
-	lipgloss.SetColorProfile(termenv.ANSI256)
+func (a PrintFileDiffAction) Execute(ctx context.Context) error {
+	if output.IsJSON() {
+		output.Emit("file_diff", map[string]interface{}{"file_path": a.FilePath, "diff": a.DiffStr})
+		return nil
+	}
+
+	lipgloss.SetColorProfile(colorProfile())
 
 	diffBoxStyle := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
@@ -1,11 +1,15 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/codecrafters-io/cli/internal/output"
+	"github.com/fatih/color"
 	"github.com/mitchellh/go-wordwrap"
+	"github.com/muesli/termenv"
 )
 
 type PrintMessageAction struct {
@@ -22,7 +26,7 @@ func NewPrintMessageAction(argsJson json.RawMessage) (PrintMessageAction, error)
 	return printMessageAction, nil
 }
 
-func (a PrintMessageAction) Execute() error {
+func (a PrintMessageAction) Execute(ctx context.Context) error {
 	wrapped := wordwrap.WrapString(a.Text, 79)
 
 	lineFormat := "%s\n"
@@ -42,9 +46,28 @@ func (a PrintMessageAction) Execute() error {
 		return fmt.Errorf("invalid color: %s", a.Color)
 	}
 
+	if output.IsJSON() {
+		output.EmitMessage(a.Color, a.Text)
+		return nil
+	}
+
+	if color.NoColor {
+		lineFormat = "%s\n"
+	}
+
 	for _, line := range strings.Split(wrapped, "\n") {
 		fmt.Printf(lineFormat, line)
 	}
 
 	return nil
 }
+
+// colorProfile returns the profile used for lipgloss styles, which is colorless when colors are disabled (e.g. with
+// `codecrafters config set color never`)
+func colorProfile() termenv.Profile {
+	if color.NoColor {
+		return termenv.Ascii
+	}
+
+	return termenv.ANSI256
+}
@@ -3,10 +3,14 @@ package actions
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/codecrafters-io/cli/internal/output"
+	"github.com/fatih/color"
 )
 
 // The maximum delay between prints in seconds
@@ -43,11 +47,9 @@ func (a PrintProgressBarAction) delayBetweenPrintsInMilliseconds(printsLeft int)
 	return min(maxDelayBetweenPrintsInSeconds*1000, 1000*a.ExpectedDelayInSeconds/a.numberOfPrints())
 }
 
-func (a PrintProgressBarAction) Execute() error {
-	return a.ExecuteWithContext(context.Background())
-}
-
-func (a PrintProgressBarAction) ExecuteWithContext(ctx context.Context) error {
+// Execute prints the progress bar up to 100%. If ctx is cancelled because what's being waited for is done
+// (errInProgressActionsFinished), the remaining prints happen without delay. Any other cancellation stops it.
+func (a PrintProgressBarAction) Execute(ctx context.Context) error {
 	contextIsCancelled := false
 	lastPrintedPercentage := 0
 
@@ -79,10 +81,12 @@ func (a PrintProgressBarAction) ExecuteWithContext(ctx context.Context) error {
 			percentageToPrint = lastPrintedPercentage
 		}
 
-		// Use ANSI color codes for green (same pattern as print_message.go)
-		greenStart := "\033[32m"
-		greenEnd := "\033[0m"
-		fmt.Printf("[%s%s%s%s] %s%s%s\n", greenStart, bars, greenEnd, strings.Repeat(" ", numberOfSpaces), greenStart, fmt.Sprintf("%d%%", percentageToPrint), greenEnd)
+		if output.IsJSON() {
+			output.Emit("progress", map[string]interface{}{"percentage": percentageToPrint})
+		} else {
+			a.printProgressBar(bars, numberOfSpaces, percentageToPrint)
+		}
+
 		lastPrintedPercentage = percentageToPrint
 
 		// If the context is cancelled, keep looping until we print all bars and exit (with no delay)
@@ -98,6 +102,10 @@ func (a PrintProgressBarAction) ExecuteWithContext(ctx context.Context) error {
 		// If the context is still active, sleep for the max delay between prints
 		select {
 		case <-ctx.Done():
+			if !errors.Is(context.Cause(ctx), errInProgressActionsFinished) {
+				return ctx.Err()
+			}
+
 			contextIsCancelled = true
 			continue
 		case <-time.After(time.Duration(sleepDurationInMilliseconds) * time.Millisecond):
@@ -107,3 +115,14 @@ func (a PrintProgressBarAction) ExecuteWithContext(ctx context.Context) error {
 
 	return nil
 }
+
+func (a PrintProgressBarAction) printProgressBar(bars string, numberOfSpaces int, percentage int) {
+	// Use ANSI color codes for green (same pattern as print_message.go)
+	greenStart := "\033[32m"
+	greenEnd := "\033[0m"
+	if color.NoColor {
+		greenStart, greenEnd = "", ""
+	}
+
+	fmt.Printf("[%s%s%s%s] %s%s%s\n", greenStart, bars, greenEnd, strings.Repeat(" ", numberOfSpaces), greenStart, fmt.Sprintf("%d%%", percentage), greenEnd)
+}
@@ -1,12 +1,13 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/termenv"
+	"github.com/codecrafters-io/cli/internal/output"
 )
 
 type PrintTerminalCommandsBoxAction struct {
@@ -22,8 +23,13 @@ func NewPrintTerminalCommandsBoxAction(argsJson json.RawMessage) (PrintTerminalC
 	return printTerminalCommandsBoxAction, nil
 }
 
-func (a PrintTerminalCommandsBoxAction) Execute() error {
-	lipgloss.SetColorProfile(termenv.ANSI256)
+func (a PrintTerminalCommandsBoxAction) Execute(ctx context.Context) error {
+	if output.IsJSON() {
+		output.Emit("terminal_commands", map[string]interface{}{"commands": a.Commands})
+		return nil
+	}
+
+	lipgloss.SetColorProfile(colorProfile())
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
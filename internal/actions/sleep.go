@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -18,8 +19,19 @@ func NewSleepAction(argsJson json.RawMessage) (SleepAction, error) {
 	return sleepAction, nil
 }
 
-func (a SleepAction) Execute() error {
-	time.Sleep(time.Duration(a.DurationInMilliseconds) * time.Millisecond)
+func (a SleepAction) Execute(ctx context.Context) error {
+	return sleep(ctx, time.Duration(a.DurationInMilliseconds)*time.Millisecond)
+}
+
+// sleep waits for duration, or returns ctx's error if ctx is cancelled first
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
 
-	return nil
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -1,12 +1,15 @@
 package actions
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 
-	logstream_redis "github.com/codecrafters-io/logstream/redis"
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/output"
 )
 
 type StreamLogsAction struct {
@@ -22,16 +25,43 @@ func NewStreamLogsAction(argsJson json.RawMessage) (StreamLogsAction, error) {
 	return streamLogsAction, nil
 }
 
-func (a StreamLogsAction) Execute() error {
-	consumer, err := logstream_redis.NewConsumer(a.LogstreamURL)
+func (a StreamLogsAction) Execute(ctx context.Context) error {
+	consumer, err := client.NewLogstreamConsumer(ctx, a.LogstreamURL)
 	if err != nil {
 		return fmt.Errorf("failed to create logstream consumer: %w", err)
 	}
 
-	_, err = io.Copy(os.Stdout, consumer)
+	defer consumer.Close()
+
+	return StreamLogs(consumer)
+}
+
+// StreamLogs renders tester logs read from reader. It's used for both remote and local test runs.
+func StreamLogs(reader io.Reader) error {
+	if output.IsJSON() {
+		return streamLogLineEvents(reader)
+	}
+
+	_, err := io.Copy(os.Stdout, reader)
 	if err != nil {
 		return fmt.Errorf("failed to read from stream: %w", err)
 	}
 
 	return nil
 }
+
+// streamLogLineEvents emits a log_line event for each line read from reader, without colors
+func streamLogLineEvents(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		output.Emit("log_line", map[string]interface{}{"text": output.StripANSI(scanner.Text())})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read from stream: %w", err)
+	}
+
+	return nil
+}
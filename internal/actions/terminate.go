@@ -1,14 +1,25 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
-	"os"
+	"fmt"
 )
 
 type TerminateAction struct {
 	ExitCode int `json:"exit_code"`
 }
 
+// ExitError is returned by TerminateAction. Instead of exiting right away, the error is returned up to main (which exits
+// with ExitCode), so that deferred cleanups (like removing temp directories) run.
+type ExitError struct {
+	ExitCode int
+}
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("terminated with exit code %d", e.ExitCode)
+}
+
 func NewTerminateAction(argsJson json.RawMessage) (TerminateAction, error) {
 	var terminateAction TerminateAction
 	if err := json.Unmarshal(argsJson, &terminateAction); err != nil {
@@ -18,8 +29,6 @@ func NewTerminateAction(argsJson json.RawMessage) (TerminateAction, error) {
 	return terminateAction, nil
 }
 
-func (a TerminateAction) Execute() error {
-	os.Exit(a.ExitCode)
-
-	return nil
+func (a TerminateAction) Execute(ctx context.Context) error {
+	return ExitError{ExitCode: a.ExitCode}
 }
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/levigross/grequests"
+)
+
+// requestIdHeader is the header the server identifies each request with. Quoting it lets support find the request in
+// the server's logs.
+const requestIdHeader = "X-Request-Id"
+
+// APIError is returned by all CodecraftersClient methods when a request to the CodeCrafters API fails
+type APIError struct {
+	// Operation describes the request, e.g. "fetch stage list from CodeCrafters"
+	Operation string
+
+	// Endpoint is the path requested, e.g. "/services/cli/fetch_stage_list"
+	Endpoint string
+
+	// StatusCode is 0 if no response was received
+	StatusCode int
+
+	// Message is the server's error_message. It's meant to be shown to users as is.
+	Message string
+
+	// RequestId is the server's id for the request, empty if no response was received. Error() always includes it, so
+	// users can mention it when reporting a problem.
+	RequestId string
+
+	// Retryable is true if the failure is likely to be transient, so trying again later might work
+	Retryable bool
+
+	// Err is the underlying error when there's no (valid) response, e.g. a connection error
+	Err error
+}
+
+func (e APIError) Error() string {
+	var message string
+	if e.Message != "" {
+		message = e.Message
+	} else if e.Err != nil {
+		message = fmt.Sprintf("failed to %s: %s", e.Operation, e.Err)
+	} else {
+		message = fmt.Sprintf("failed to %s. status code: %d", e.Operation, e.StatusCode)
+	}
+
+	if e.RequestId != "" {
+		message += fmt.Sprintf(" (request id: %s)", e.RequestId)
+	}
+
+	return message
+}
+
+func (e APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError returns an APIError for a request to endpoint. response is nil if no response was received.
+func newAPIError(operation string, endpoint string, response *grequests.Response, err error) APIError {
+	apiError := APIError{
+		Operation: operation,
+		Endpoint:  endpoint,
+		Err:       err,
+	}
+
+	if response == nil || response.RawResponse == nil {
+		// No response: a connection error (which the transport already retried) or a timeout
+		apiError.Retryable = err != nil && !errors.Is(err, context.Canceled)
+		return apiError
+	}
+
+	apiError.StatusCode = response.StatusCode
+	apiError.RequestId = response.Header.Get(requestIdHeader)
+	apiError.Retryable = isTransientStatusCode(response.StatusCode)
+
+	return apiError
+}
+
+// checkResponse returns an APIError if a request failed or its response isn't a success. The server's error_message is
+// used if the response has one.
+func checkResponse(operation string, endpoint string, response *grequests.Response, err error) error {
+	if err != nil {
+		return newAPIError(operation, endpoint, nil, err)
+	}
+
+	if response.Ok {
+		return nil
+	}
+
+	apiError := newAPIError(operation, endpoint, response, nil)
+
+	body := response.Bytes()
+	utils.Logger.Debug().Msgf("%s responded with %d: %s", endpoint, response.StatusCode, body)
+
+	var errorResponse struct {
+		ErrorMessage string `json:"error_message"`
+	}
+
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiError.Message = errorResponse.ErrorMessage
+	}
+
+	return apiError
+}
+
+// parseResponse decodes a successful response's JSON body into v
+func parseResponse(operation string, endpoint string, response *grequests.Response, v interface{}) error {
+	if err := json.Unmarshal(response.Bytes(), v); err != nil {
+		return newAPIError(operation, endpoint, response, fmt.Errorf("parse response: %w", err))
+	}
+
+	return nil
+}
+
+// serverError returns the APIError for a response with is_error set
+func serverError(operation string, endpoint string, response *grequests.Response, errorMessage string) APIError {
+	apiError := newAPIError(operation, endpoint, response, nil)
+	apiError.Message = errorMessage
+
+	if apiError.Message == "" {
+		apiError.Err = errors.New("unknown error")
+	}
+
+	return apiError
+}
+
+// isTransientStatusCode returns true for the statuses that retryTransport retries
+func isTransientStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIServer(t *testing.T, statusCode int, body string) CodecraftersClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIdHeader, "req-123")
+		w.WriteHeader(statusCode)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return CodecraftersClient{ServerUrl: server.URL}
+}
+
+func TestAPIErrorForFailedResponse(t *testing.T) {
+	codecraftersClient := newAPIServer(t, http.StatusInternalServerError, "Internal Server Error")
+
+	_, err := codecraftersClient.FetchStageList(context.Background(), "abc123")
+
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "/services/cli/fetch_stage_list", apiErr.Endpoint)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, "req-123", apiErr.RequestId)
+	assert.Empty(t, apiErr.Message)
+	assert.False(t, apiErr.Retryable)
+	assert.Equal(t, "failed to fetch stage list from CodeCrafters. status code: 500 (request id: req-123)", err.Error())
+}
+
+func TestAPIErrorUsesServerErrorMessage(t *testing.T) {
+	codecraftersClient := newAPIServer(t, http.StatusNotFound, `{"error_message": "Repository not found."}`)
+
+	_, err := codecraftersClient.FetchRepositoryBuildpack(context.Background(), "abc123")
+
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Repository not found. (request id: req-123)", err.Error())
+}
+
+func TestAPIErrorForIsErrorResponse(t *testing.T) {
+	codecraftersClient := newAPIServer(t, http.StatusForbidden, `{"is_error": true, "error_message": "You've hit the daily limit."}`)
+
+	response, err := codecraftersClient.CreateSubmission(context.Background(), "abc123", "deadbeef", "test", "current_and_previous_descending", nil)
+
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.True(t, response.IsError)
+	assert.Equal(t, "/services/cli/create_submission", apiErr.Endpoint)
+	assert.Equal(t, "req-123", apiErr.RequestId)
+	assert.Equal(t, "You've hit the daily limit. (request id: req-123)", err.Error())
+}
+
+func TestAPIErrorForConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := CodecraftersClient{ServerUrl: server.URL}.FetchBuildpacks(context.Background(), "abc123")
+
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Zero(t, apiErr.StatusCode)
+	assert.True(t, apiErr.Retryable)
+	assert.ErrorContains(t, err, "failed to fetch buildpacks from CodeCrafters: ")
+}
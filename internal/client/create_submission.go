@@ -1,9 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
+	"context"
 
 	"github.com/levigross/grequests"
 )
@@ -21,36 +19,43 @@ type CreateSubmissionResponse struct {
 	ErrorMessage string `json:"error_message"`
 }
 
-func (c CodecraftersClient) CreateSubmission(repositoryId string, commitSha string, command string, stageSelectionStrategy string) (CreateSubmissionResponse, error) {
-	response, err := grequests.Post(c.ServerUrl+"/services/cli/create_submission", &grequests.RequestOptions{
-		JSON: map[string]interface{}{
-			"repository_id":            repositoryId,
-			"commit_sha":               commitSha,
-			"command":                  command,
-			"stage_selection_strategy": stageSelectionStrategy,
-		},
-		Headers: c.headers(),
-	})
+// CreateSubmission creates a submission for commitSha. stageSlugs is only used when stageSelectionStrategy is "specific_stages".
+func (c CodecraftersClient) CreateSubmission(ctx context.Context, repositoryId string, commitSha string, command string, stageSelectionStrategy string, stageSlugs []string) (CreateSubmissionResponse, error) {
+	operation, endpoint := "submit code to CodeCrafters", "/services/cli/create_submission"
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to submit code to CodeCrafters: %s\n", err)
-		return CreateSubmissionResponse{}, err
+	requestJson := map[string]interface{}{
+		"repository_id":            repositoryId,
+		"commit_sha":               commitSha,
+		"command":                  command,
+		"stage_selection_strategy": stageSelectionStrategy,
+	}
+
+	if len(stageSlugs) > 0 {
+		requestJson["stage_slugs"] = stageSlugs
 	}
 
-	if !response.Ok && response.StatusCode != 403 {
-		return CreateSubmissionResponse{}, fmt.Errorf("failed to submit code to CodeCrafters. status code: %d, body: %s", response.StatusCode, response.String())
+	response, err := grequests.Post(c.ServerUrl+endpoint, &grequests.RequestOptions{
+		JSON:       requestJson,
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
+	})
+
+	// 403s explain why the submission isn't allowed in the same format as successful responses
+	if err != nil || response.StatusCode != 403 {
+		if err := checkResponse(operation, endpoint, response, err); err != nil {
+			return CreateSubmissionResponse{}, err
+		}
 	}
 
 	createSubmissionResponse := CreateSubmissionResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &createSubmissionResponse)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to submit code to CodeCrafters: %s\n", err)
+	if err := parseResponse(operation, endpoint, response, &createSubmissionResponse); err != nil {
 		return CreateSubmissionResponse{}, err
 	}
 
 	if createSubmissionResponse.IsError {
-		return createSubmissionResponse, fmt.Errorf("%s", createSubmissionResponse.ErrorMessage)
+		return createSubmissionResponse, serverError(operation, endpoint, response, createSubmissionResponse.ErrorMessage)
 	}
 
 	return createSubmissionResponse, nil
@@ -1,8 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/levigross/grequests"
@@ -14,35 +13,34 @@ type FetchAutofixRequestResponse struct {
 	Status       string `json:"status"`
 }
 
-func (c CodecraftersClient) FetchAutofixRequest(submissionId string) (FetchAutofixRequestResponse, error) {
-	utils.Logger.Debug().Msgf("GET /services/cli/fetch_autofix_request?submission_id=%s", submissionId)
+func (c CodecraftersClient) FetchAutofixRequest(ctx context.Context, submissionId string) (FetchAutofixRequestResponse, error) {
+	operation, endpoint := "fetch autofix request status from CodeCrafters", "/services/cli/fetch_autofix_request"
 
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_autofix_request", c.ServerUrl), &grequests.RequestOptions{
+	utils.Logger.Debug().Msgf("GET %s?submission_id=%s", endpoint, submissionId)
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"submission_id": submissionId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchAutofixRequestResponse{}, fmt.Errorf("failed to fetch autofix request status from CodeCrafters: %s", err)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchAutofixRequestResponse{}, err
 	}
 
 	utils.Logger.Debug().Msgf("response: %s", response.String())
 
-	if !response.Ok {
-		return FetchAutofixRequestResponse{}, fmt.Errorf("failed to fetch autofix request status from CodeCrafters. status code: %d", response.StatusCode)
-	}
-
 	fetchAutofixRequestResponse := FetchAutofixRequestResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchAutofixRequestResponse)
-	if err != nil {
-		return FetchAutofixRequestResponse{}, fmt.Errorf("failed to parse fetch autofix request response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchAutofixRequestResponse); err != nil {
+		return FetchAutofixRequestResponse{}, err
 	}
 
 	if fetchAutofixRequestResponse.IsError {
-		return FetchAutofixRequestResponse{}, fmt.Errorf("%s", fetchAutofixRequestResponse.ErrorMessage)
+		return FetchAutofixRequestResponse{}, serverError(operation, endpoint, response, fetchAutofixRequestResponse.ErrorMessage)
 	}
 
 	return fetchAutofixRequestResponse, nil
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"time"
 
@@ -17,15 +17,16 @@ type FetchBuildStatusResponse struct {
 	IsError      bool   `json:"is_error"`
 }
 
-func (c CodecraftersClient) FetchBuild(buildId string) (FetchBuildStatusResponse, error) {
+func (c CodecraftersClient) FetchBuild(ctx context.Context, buildId string) (FetchBuildStatusResponse, error) {
 	var fetchBuildResponse FetchBuildStatusResponse
 
 	err := retry.Do(
 		func() error {
 			var err error
-			fetchBuildResponse, err = c.doFetchBuild(buildId)
+			fetchBuildResponse, err = c.doFetchBuild(ctx, buildId)
 			if err != nil {
-				return err
+				// Failed requests are already retried by the transport, only keep polling for a terminal status
+				return retry.Unrecoverable(err)
 			}
 
 			if fetchBuildResponse.Status != "failure" && fetchBuildResponse.Status != "success" {
@@ -39,10 +40,11 @@ func (c CodecraftersClient) FetchBuild(buildId string) (FetchBuildStatusResponse
 		retry.MaxDelay(2*time.Second),
 		retry.Delay(100*time.Millisecond),
 		retry.LastErrorOnly(true),
+		retry.Context(ctx),
 	)
 
 	if err != nil {
-		if fetchBuildResponse.Status != "failure" && fetchBuildResponse.Status != "success" {
+		if fetchBuildResponse.Status != "failure" && fetchBuildResponse.Status != "success" && ctx.Err() == nil {
 			sentry.CaptureException(err)
 		}
 
@@ -52,27 +54,26 @@ func (c CodecraftersClient) FetchBuild(buildId string) (FetchBuildStatusResponse
 	return fetchBuildResponse, nil
 }
 
-func (c CodecraftersClient) doFetchBuild(buildId string) (FetchBuildStatusResponse, error) {
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_test_runner_build", c.ServerUrl), &grequests.RequestOptions{
+func (c CodecraftersClient) doFetchBuild(ctx context.Context, buildId string) (FetchBuildStatusResponse, error) {
+	operation, endpoint := "fetch build result from CodeCrafters", "/services/cli/fetch_test_runner_build"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"test_runner_build_id": buildId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchBuildStatusResponse{}, fmt.Errorf("failed to fetch build result from CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return FetchBuildStatusResponse{}, fmt.Errorf("failed to fetch build result from CodeCrafters. status code: %d", response.StatusCode)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchBuildStatusResponse{}, err
 	}
 
 	fetchBuildResponse := FetchBuildStatusResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchBuildResponse)
-	if err != nil {
-		return FetchBuildStatusResponse{}, fmt.Errorf("failed to fetch build result from CodeCrafters: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchBuildResponse); err != nil {
+		return FetchBuildStatusResponse{}, err
 	}
 
 	return fetchBuildResponse, nil
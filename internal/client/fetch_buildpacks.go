@@ -1,8 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
 	"github.com/levigross/grequests"
 )
@@ -13,31 +12,30 @@ type FetchBuildpacksResponse struct {
 	IsError      bool            `json:"is_error"`
 }
 
-func (c CodecraftersClient) FetchBuildpacks(repositoryId string) (FetchBuildpacksResponse, error) {
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_buildpacks", c.ServerUrl), &grequests.RequestOptions{
+func (c CodecraftersClient) FetchBuildpacks(ctx context.Context, repositoryId string) (FetchBuildpacksResponse, error) {
+	operation, endpoint := "fetch buildpacks from CodeCrafters", "/services/cli/fetch_buildpacks"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"repository_id": repositoryId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchBuildpacksResponse{}, fmt.Errorf("failed to fetch buildpacks from CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return FetchBuildpacksResponse{}, fmt.Errorf("failed to fetch buildpacks from CodeCrafters. status code: %d", response.StatusCode)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchBuildpacksResponse{}, err
 	}
 
 	fetchBuildpacksResponse := FetchBuildpacksResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchBuildpacksResponse)
-	if err != nil {
-		return FetchBuildpacksResponse{}, fmt.Errorf("failed to fetch buildpacks from CodeCrafters: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchBuildpacksResponse); err != nil {
+		return FetchBuildpacksResponse{}, err
 	}
 
 	if fetchBuildpacksResponse.IsError {
-		return fetchBuildpacksResponse, fmt.Errorf("%s", fetchBuildpacksResponse.ErrorMessage)
+		return fetchBuildpacksResponse, serverError(operation, endpoint, response, fetchBuildpacksResponse.ErrorMessage)
 	}
 
 	return fetchBuildpacksResponse, nil
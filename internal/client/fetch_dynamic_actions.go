@@ -1,7 +1,7 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/levigross/grequests"
@@ -11,7 +11,9 @@ type FetchDynamicActionsResponse struct {
 	ActionDefinitions []ActionDefinition `json:"actions"`
 }
 
-func (c CodecraftersClient) FetchDynamicActions(eventName string, eventParams map[string]interface{}) (FetchDynamicActionsResponse, error) {
+func (c CodecraftersClient) FetchDynamicActions(ctx context.Context, eventName string, eventParams map[string]interface{}) (FetchDynamicActionsResponse, error) {
+	operation, endpoint := "fetch dynamic actions from CodeCrafters", "/services/cli/fetch_dynamic_actions"
+
 	queryParams := map[string]string{
 		"event_name": eventName,
 	}
@@ -20,24 +22,21 @@ func (c CodecraftersClient) FetchDynamicActions(eventName string, eventParams ma
 		queryParams[fmt.Sprintf("event_params[%s]", key)] = fmt.Sprintf("%v", value)
 	}
 
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_dynamic_actions", c.ServerUrl), &grequests.RequestOptions{
-		Params:  queryParams,
-		Headers: c.headers(),
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
+		Params:     queryParams,
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchDynamicActionsResponse{}, fmt.Errorf("failed to fetch dynamic actions from CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return FetchDynamicActionsResponse{}, fmt.Errorf("failed to fetch dynamic actions from CodeCrafters. status code: %d, body: %s", response.StatusCode, response.String())
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchDynamicActionsResponse{}, err
 	}
 
 	fetchDynamicActionsResponse := FetchDynamicActionsResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchDynamicActionsResponse)
-	if err != nil {
-		return FetchDynamicActionsResponse{}, fmt.Errorf("failed to parse fetch dynamic actions response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchDynamicActionsResponse); err != nil {
+		return FetchDynamicActionsResponse{}, err
 	}
 
 	return fetchDynamicActionsResponse, nil
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+
+	"github.com/levigross/grequests"
+)
+
+type FetchLatestCLIVersionResponse struct {
+	// Version is the release tag, e.g. v55
+	Version        string `json:"version"`
+	DownloadUrl    string `json:"download_url"`
+	ChecksumSHA256 string `json:"checksum_sha256"`
+
+	ErrorMessage string `json:"error_message"`
+	IsError      bool   `json:"is_error"`
+}
+
+// FetchLatestCLIVersion returns the latest release of the CLI, and where to download its archive for goos/goarch
+func (c CodecraftersClient) FetchLatestCLIVersion(ctx context.Context, goos string, goarch string) (FetchLatestCLIVersionResponse, error) {
+	operation, endpoint := "fetch latest CLI version from CodeCrafters", "/services/cli/fetch_latest_version"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
+		Params: map[string]string{
+			"os":   goos,
+			"arch": goarch,
+		},
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
+	})
+
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchLatestCLIVersionResponse{}, err
+	}
+
+	fetchLatestCLIVersionResponse := FetchLatestCLIVersionResponse{}
+
+	if err := parseResponse(operation, endpoint, response, &fetchLatestCLIVersionResponse); err != nil {
+		return FetchLatestCLIVersionResponse{}, err
+	}
+
+	if fetchLatestCLIVersionResponse.IsError {
+		return fetchLatestCLIVersionResponse, serverError(operation, endpoint, response, fetchLatestCLIVersionResponse.ErrorMessage)
+	}
+
+	return fetchLatestCLIVersionResponse, nil
+}
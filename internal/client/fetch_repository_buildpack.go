@@ -1,8 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
 	"github.com/levigross/grequests"
 )
@@ -13,31 +12,30 @@ type FetchRepositoryBuildpackResponse struct {
 	IsError      bool          `json:"is_error"`
 }
 
-func (c CodecraftersClient) FetchRepositoryBuildpack(repositoryId string) (FetchRepositoryBuildpackResponse, error) {
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_repository_buildpack", c.ServerUrl), &grequests.RequestOptions{
+func (c CodecraftersClient) FetchRepositoryBuildpack(ctx context.Context, repositoryId string) (FetchRepositoryBuildpackResponse, error) {
+	operation, endpoint := "fetch repository buildpack from CodeCrafters", "/services/cli/fetch_repository_buildpack"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"repository_id": repositoryId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchRepositoryBuildpackResponse{}, fmt.Errorf("failed to fetch repository buildpack from CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return FetchRepositoryBuildpackResponse{}, fmt.Errorf("failed to fetch repository buildpack from CodeCrafters. status code: %d", response.StatusCode)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchRepositoryBuildpackResponse{}, err
 	}
 
 	fetchRepositoryBuildpackResponse := FetchRepositoryBuildpackResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchRepositoryBuildpackResponse)
-	if err != nil {
-		return FetchRepositoryBuildpackResponse{}, fmt.Errorf("failed to parse fetch repository buildpack response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchRepositoryBuildpackResponse); err != nil {
+		return FetchRepositoryBuildpackResponse{}, err
 	}
 
 	if fetchRepositoryBuildpackResponse.IsError {
-		return fetchRepositoryBuildpackResponse, fmt.Errorf("%s", fetchRepositoryBuildpackResponse.ErrorMessage)
+		return fetchRepositoryBuildpackResponse, serverError(operation, endpoint, response, fetchRepositoryBuildpackResponse.ErrorMessage)
 	}
 
 	return fetchRepositoryBuildpackResponse, nil
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/levigross/grequests"
@@ -24,31 +24,41 @@ type FetchStageListResponse struct {
 	IsError      bool    `json:"is_error"`
 }
 
-func (c CodecraftersClient) FetchStageList(repositoryId string) (FetchStageListResponse, error) {
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_stage_list", c.ServerUrl), &grequests.RequestOptions{
+// CurrentStageIndex returns the index of the stage the user is currently working on, or -1 if there isn't one
+func (r FetchStageListResponse) CurrentStageIndex() int {
+	for i := range r.Stages {
+		if r.Stages[i].IsCurrent {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (c CodecraftersClient) FetchStageList(ctx context.Context, repositoryId string) (FetchStageListResponse, error) {
+	operation, endpoint := "fetch stage list from CodeCrafters", "/services/cli/fetch_stage_list"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"repository_id": repositoryId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchStageListResponse{}, fmt.Errorf("failed to fetch stage list from CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return FetchStageListResponse{}, fmt.Errorf("failed to fetch stage list from CodeCrafters. status code: %d", response.StatusCode)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchStageListResponse{}, err
 	}
 
 	fetchStageListResponse := FetchStageListResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchStageListResponse)
-	if err != nil {
-		return FetchStageListResponse{}, fmt.Errorf("failed to parse fetch stage list response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchStageListResponse); err != nil {
+		return FetchStageListResponse{}, err
 	}
 
 	if fetchStageListResponse.IsError {
-		return fetchStageListResponse, fmt.Errorf("%s", fetchStageListResponse.ErrorMessage)
+		return fetchStageListResponse, serverError(operation, endpoint, response, fetchStageListResponse.ErrorMessage)
 	}
 
 	return fetchStageListResponse, nil
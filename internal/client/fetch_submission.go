@@ -1,7 +1,7 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"time"
 
@@ -16,15 +16,16 @@ type FetchSubmissionResponse struct {
 	Status       string `json:"status"`
 }
 
-func (c CodecraftersClient) FetchSubmission(submissionId string) (FetchSubmissionResponse, error) {
+func (c CodecraftersClient) FetchSubmission(ctx context.Context, submissionId string) (FetchSubmissionResponse, error) {
 	var fetchSubmissionResponse FetchSubmissionResponse
 
 	err := retry.Do(
 		func() error {
 			var err error
-			fetchSubmissionResponse, err = c.doFetchSubmission(submissionId)
+			fetchSubmissionResponse, err = c.doFetchSubmission(ctx, submissionId)
 			if err != nil {
-				return err
+				// Failed requests are already retried by the transport, only keep polling for a terminal status
+				return retry.Unrecoverable(err)
 			}
 
 			if fetchSubmissionResponse.Status != "failure" && fetchSubmissionResponse.Status != "success" {
@@ -38,6 +39,7 @@ func (c CodecraftersClient) FetchSubmission(submissionId string) (FetchSubmissio
 		retry.MaxDelay(2*time.Second),
 		retry.Delay(500*time.Millisecond),
 		retry.LastErrorOnly(true),
+		retry.Context(ctx),
 	)
 
 	if err != nil {
@@ -47,31 +49,30 @@ func (c CodecraftersClient) FetchSubmission(submissionId string) (FetchSubmissio
 	return fetchSubmissionResponse, nil
 }
 
-func (c CodecraftersClient) doFetchSubmission(submissionId string) (FetchSubmissionResponse, error) {
-	utils.Logger.Debug().Msgf("GET /services/cli/fetch_submission?submission_id=%s", submissionId)
+func (c CodecraftersClient) doFetchSubmission(ctx context.Context, submissionId string) (FetchSubmissionResponse, error) {
+	operation, endpoint := "fetch submission result from CodeCrafters", "/services/cli/fetch_submission"
 
-	response, err := grequests.Get(fmt.Sprintf("%s/services/cli/fetch_submission", c.ServerUrl), &grequests.RequestOptions{
+	utils.Logger.Debug().Msgf("GET %s?submission_id=%s", endpoint, submissionId)
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		Params: map[string]string{
 			"submission_id": submissionId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
 	})
 
-	if err != nil {
-		return FetchSubmissionResponse{}, fmt.Errorf("failed to fetch submission result from CodeCrafters: %s", err)
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchSubmissionResponse{}, err
 	}
 
 	utils.Logger.Debug().Msgf("response: %s", response.String())
 
-	if !response.Ok {
-		return FetchSubmissionResponse{}, fmt.Errorf("failed to fetch submission result from CodeCrafters. status code: %d", response.StatusCode)
-	}
-
 	fetchSubmissionResponse := FetchSubmissionResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &fetchSubmissionResponse)
-	if err != nil {
-		return FetchSubmissionResponse{}, fmt.Errorf("failed to fetch submission result from CodeCrafters: %s", err)
+	if err := parseResponse(operation, endpoint, response, &fetchSubmissionResponse); err != nil {
+		return FetchSubmissionResponse{}, err
 	}
 
 	return fetchSubmissionResponse, nil
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+
+	"github.com/levigross/grequests"
+)
+
+type FetchSubmissionActionsResponse struct {
+	// Actions is the list of actions to execute to follow the submission from where it currently is, e.g. streaming its
+	// logs and waiting for its result
+	Actions []ActionDefinition `json:"actions"`
+
+	CommitSHA string `json:"commit_sha"`
+	Status    string `json:"status"`
+
+	ErrorMessage string `json:"error_message"`
+	IsError      bool   `json:"is_error"`
+}
+
+// FetchSubmissionActions fetches the actions to re-attach to an existing submission
+func (c CodecraftersClient) FetchSubmissionActions(ctx context.Context, submissionId string) (FetchSubmissionActionsResponse, error) {
+	operation, endpoint := "fetch submission actions from CodeCrafters", "/services/cli/fetch_submission_actions"
+
+	response, err := grequests.Get(c.ServerUrl+endpoint, &grequests.RequestOptions{
+		Params: map[string]string{
+			"submission_id": submissionId,
+		},
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
+	})
+
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return FetchSubmissionActionsResponse{}, err
+	}
+
+	fetchSubmissionActionsResponse := FetchSubmissionActionsResponse{}
+
+	if err := parseResponse(operation, endpoint, response, &fetchSubmissionActionsResponse); err != nil {
+		return FetchSubmissionActionsResponse{}, err
+	}
+
+	if fetchSubmissionActionsResponse.IsError {
+		return fetchSubmissionActionsResponse, serverError(operation, endpoint, response, fetchSubmissionActionsResponse.ErrorMessage)
+	}
+
+	return fetchSubmissionActionsResponse, nil
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/config"
+)
+
+// rootCAs are the certificates that servers are verified against: the system's, plus those from the ca_bundle
+// setting. nil means only the system's.
+var rootCAs *x509.CertPool
+
+// apiRequestTimeout is how long a request to the CodeCrafters API can take (including retries), so that a backend that
+// doesn't respond doesn't block forever
+const apiRequestTimeout = 60 * time.Second
+
+// httpClient is used for downloads, apiClient for requests to the CodeCrafters API. They share a transport, and so
+// connections and the retry policy (see retryTransport).
+var httpClient, apiClient = newHTTPClients()
+
+// SetupHTTPClient applies the ca_bundle setting (CODECRAFTERS_CA_BUNDLE) to all connections: requests to CodeCrafters,
+// the logstream connection and downloads. It must be called after the config is loaded.
+func SetupHTTPClient() error {
+	caBundlePath := config.Get("ca_bundle")
+	if caBundlePath == "" {
+		return nil
+	}
+
+	pemCerts, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return fmt.Errorf("Failed to read the CA bundle at %s: %s", caBundlePath, err)
+	}
+
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		// Not supported on some platforms, the bundle is used on its own then
+		certPool = x509.NewCertPool()
+	}
+
+	if !certPool.AppendCertsFromPEM(pemCerts) {
+		return fmt.Errorf("No certificates found in the CA bundle at %s. Expected PEM encoded certificates.", caBundlePath)
+	}
+
+	rootCAs = certPool
+	httpClient, apiClient = newHTTPClients()
+
+	return nil
+}
+
+// HTTPClient returns the client used for all HTTP requests
+func HTTPClient() *http.Client {
+	return httpClient
+}
+
+// TLSConfig returns the TLS settings for a connection to serverName
+func TLSConfig(serverName string) *tls.Config {
+	return &tls.Config{ServerName: serverName, RootCAs: rootCAs}
+}
+
+// ProxyURL returns the proxy used to connect to targetUrl, or nil if the connection is direct. Proxies are set with
+// HTTPS_PROXY (or HTTP_PROXY for http URLs), NO_PROXY lists the hosts that are connected to directly.
+func ProxyURL(targetUrl *url.URL) (*url.URL, error) {
+	return http.ProxyFromEnvironment(&http.Request{URL: targetUrl})
+}
+
+func newHTTPClients() (*http.Client, *http.Client) {
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	baseTransport.Proxy = http.ProxyFromEnvironment
+	baseTransport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+
+	transport := &retryTransport{base: baseTransport}
+
+	return &http.Client{Transport: transport}, &http.Client{Transport: transport, Timeout: apiRequestTimeout}
+}
+
+// DialContext connects to address (host:port), through a proxy if one is set for it. Proxies are tunneled through with
+// CONNECT, so that this works for any protocol. address is treated as an https URL when picking the proxy.
+func DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	proxyUrl, err := ProxyURL(&url.URL{Scheme: "https", Host: address})
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy: %w", err)
+	}
+
+	if proxyUrl == nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	proxyAddress := proxyUrl.Host
+	if proxyUrl.Port() == "" {
+		defaultPort := "80"
+		if proxyUrl.Scheme == "https" {
+			defaultPort = "443"
+		}
+
+		proxyAddress = net.JoinHostPort(proxyUrl.Hostname(), defaultPort)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("connect to proxy %s: %w", proxyAddress, err)
+	}
+
+	if proxyUrl.Scheme == "https" {
+		conn = tls.Client(conn, TLSConfig(proxyUrl.Hostname()))
+	}
+
+	if err := connectThroughProxy(conn, proxyUrl, address); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func connectThroughProxy(conn net.Conn, proxyUrl *url.URL, address string) error {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+
+	if proxyUrl.User != nil {
+		password, _ := proxyUrl.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyUrl.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := request.Write(conn); err != nil {
+		return fmt.Errorf("send CONNECT to proxy: %w", err)
+	}
+
+	// The target doesn't send anything before the client does, so nothing is lost by discarding this reader
+	response, err := http.ReadResponse(bufio.NewReader(conn), request)
+	if err != nil {
+		return fmt.Errorf("read CONNECT response from proxy: %w", err)
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy refused to connect to %s: %s", address, response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// logstreamConsumer reads tester logs from a logstream (a redis stream). It's the same protocol as logstream's redis
+// consumer, which doesn't allow changing how it connects.
+type logstreamConsumer struct {
+	ctx    context.Context
+	client *redis.Client
+	stream string
+
+	lastMessageID string
+	eofReached    bool
+
+	readbuf  []byte
+	consumed int
+}
+
+// NewLogstreamConsumer returns a reader for the logs at logstreamUrl (redis[s]://host:port/[db/]stream). It connects
+// with the same proxy and CA settings as HTTPClient. Reads fail once ctx is cancelled.
+func NewLogstreamConsumer(ctx context.Context, logstreamUrl string) (io.ReadCloser, error) {
+	parsedUrl, err := url.Parse(logstreamUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+
+	pathSegments := strings.FieldsFunc(parsedUrl.Path, func(r rune) bool { return r == '/' })
+
+	if len(pathSegments) == 1 {
+		pathSegments = []string{"0", pathSegments[0]} // Default db is 0
+	}
+
+	if len(pathSegments) != 2 {
+		return nil, fmt.Errorf("url path must be /db/stream_id")
+	}
+
+	parsedUrl.Path = "/" + pathSegments[0]
+
+	query, err := url.ParseQuery(parsedUrl.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("parse url query params: %w", err)
+	}
+
+	if !query.Has("dial_timeout") {
+		query.Set("dial_timeout", "30s")
+	}
+
+	parsedUrl.RawQuery = query.Encode()
+
+	options, err := redis.ParseURL(parsedUrl.String())
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+
+	// go-redis only sets up TLS itself when it dials
+	tlsConfig := options.TLSConfig
+	if tlsConfig != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	options.Dialer = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		conn, err := DialContext(ctx, network, address)
+		if err != nil || tlsConfig == nil {
+			return conn, err
+		}
+
+		return tls.Client(conn, tlsConfig), nil
+	}
+
+	return &logstreamConsumer{
+		ctx:           ctx,
+		client:        redis.NewClient(options),
+		stream:        pathSegments[1],
+		lastMessageID: "0",
+	}, nil
+}
+
+func (c *logstreamConsumer) Read(p []byte) (int, error) {
+	n := copy(p, c.readbuf[c.consumed:])
+	c.consumed += n
+
+	if err := c.eof(); n != 0 || err != nil {
+		return n, err
+	}
+
+	c.consumed = 0
+	c.readbuf = c.readbuf[:0]
+
+	streams, err := c.client.XRead(c.ctx, &redis.XReadArgs{
+		Streams: []string{c.stream, c.lastMessageID},
+		Block:   5 * time.Second,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("redis: xread: %w", err)
+	}
+
+streams:
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			eventType, ok := message.Values["event_type"].(string)
+			if !ok {
+				return 0, fmt.Errorf("no event_type in message: %v", message)
+			}
+
+			if eventType == "disconnect" {
+				c.eofReached = true
+
+				break streams
+			}
+
+			if eventType != "log" {
+				return 0, fmt.Errorf("unexpected event_type: %v", eventType)
+			}
+
+			text, ok := message.Values["bytes"].(string)
+			if !ok {
+				return 0, fmt.Errorf("malformed message: no bytes: %v", message)
+			}
+
+			copied := copy(p[n:], text)
+			n += copied
+
+			if copied < len(text) {
+				c.readbuf = append(c.readbuf, text[copied:]...)
+			}
+
+			c.lastMessageID = message.ID
+		}
+	}
+
+	return n, c.eof()
+}
+
+func (c *logstreamConsumer) Close() error {
+	return c.client.Close()
+}
+
+func (c *logstreamConsumer) eof() error {
+	if c.eofReached && c.consumed == len(c.readbuf) {
+		return io.EOF
+	}
+
+	return nil
+}
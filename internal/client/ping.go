@@ -1,8 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
 	"github.com/levigross/grequests"
 )
@@ -11,27 +10,27 @@ type PingResponse struct {
 	Actions []ActionDefinition `json:"actions"`
 }
 
-func (c CodecraftersClient) Ping(repositoryId string) (PingResponse, error) {
-	response, err := grequests.Post(c.ServerUrl+"/services/cli/ping", &grequests.RequestOptions{
+func (c CodecraftersClient) Ping(ctx context.Context, repositoryId string) (PingResponse, error) {
+	operation, endpoint := "ping CodeCrafters", "/services/cli/ping"
+
+	response, err := grequests.Post(c.ServerUrl+endpoint, &grequests.RequestOptions{
 		JSON: map[string]interface{}{
 			"repository_id": repositoryId,
 		},
-		Headers: c.headers(),
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		// Pinging has no side effects, so it's safe to retry like a GET
+		Context: withRetries(ctx),
 	})
 
-	if err != nil {
-		return PingResponse{}, fmt.Errorf("failed to ping CodeCrafters: %s", err)
-	}
-
-	if !response.Ok {
-		return PingResponse{}, fmt.Errorf("failed to ping CodeCrafters. status code: %d, body: %s", response.StatusCode, response.String())
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return PingResponse{}, err
 	}
 
 	pingResponse := PingResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &pingResponse)
-	if err != nil {
-		return PingResponse{}, fmt.Errorf("failed to parse ping response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &pingResponse); err != nil {
+		return PingResponse{}, err
 	}
 
 	return pingResponse, nil
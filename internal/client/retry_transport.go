@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/utils"
+)
+
+// The retry policy for all HTTP requests
+const (
+	maxRequestAttempts = 4
+	retryBaseDelay     = 250 * time.Millisecond
+	retryMaxDelay      = 4 * time.Second
+
+	// Retry-After values longer than this aren't waited for, the response is returned as is instead
+	maxRetryAfter = 20 * time.Second
+)
+
+// retryTransport retries requests that failed in a way that's likely to be transient: connection errors and
+// 429/502/503/504 responses. Only idempotent requests (GET, HEAD, PUT, DELETE, OPTIONS, or any request whose context
+// was marked with withRetries) are retried, since a POST could be processed twice otherwise.
+//
+// Retries are delayed with exponential backoff and full jitter, or by the response's Retry-After if it has one.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptRequest := request
+
+		if attempt > 1 && request.Body != nil {
+			// The body was consumed by the previous attempt. RoundTrippers mustn't modify the request, so a copy is sent.
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			attemptRequest = request.Clone(request.Context())
+			attemptRequest.Body = body
+		}
+
+		response, err := t.base.RoundTrip(attemptRequest)
+
+		delay, shouldRetry := retryDelay(request, response, err, attempt)
+		if !shouldRetry {
+			utils.Logger.Debug().Msgf("%s %s: %s (attempt %d/%d)", request.Method, request.URL.Redacted(), describeAttempt(response, err), attempt, maxRequestAttempts)
+			return response, err
+		}
+
+		utils.Logger.Debug().Msgf("%s %s: %s (attempt %d/%d), retrying in %s", request.Method, request.URL.Redacted(), describeAttempt(response, err), attempt, maxRequestAttempts, delay)
+
+		if response != nil {
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt, and false if the request shouldn't be retried
+func retryDelay(request *http.Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= maxRequestAttempts || request.Context().Err() != nil {
+		return 0, false
+	}
+
+	if request.Body != nil && request.GetBody == nil {
+		// The body can't be sent again
+		return 0, false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || !isIdempotent(request) {
+			return 0, false
+		}
+
+		return backoffDelay(attempt), true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if !isIdempotent(request) {
+			return 0, false
+		}
+
+		if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= maxRetryAfter
+		}
+
+		return backoffDelay(attempt), true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return backoffDelay(attempt), isIdempotent(request)
+	default:
+		return 0, false
+	}
+}
+
+type retriesContextKey struct{}
+
+// withRetries marks requests made with ctx as safe to retry regardless of their method, for endpoints like ping that
+// use POST but have no side effects
+func withRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesContextKey{}, true)
+}
+
+// isIdempotent reports whether a request can be sent again without side effects
+func isIdempotent(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	retriesAllowed, _ := request.Context().Value(retriesContextKey{}).(bool)
+
+	return retriesAllowed
+}
+
+// backoffDelay returns a random delay between 0 and retryBaseDelay * 2^(attempt-1), capped at retryMaxDelay
+func backoffDelay(attempt int) time.Duration {
+	maxDelay := retryBaseDelay << (attempt - 1)
+	if maxDelay > retryMaxDelay || maxDelay <= 0 {
+		maxDelay = retryMaxDelay
+	}
+
+	return time.Duration(rand.Int63n(int64(maxDelay) + 1))
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if retryAt, err := http.ParseTime(value); err == nil {
+		return max(time.Until(retryAt), 0), true
+	}
+
+	return 0, false
+}
+
+func describeAttempt(response *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+
+	return response.Status
+}
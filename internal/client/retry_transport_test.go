@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer returns a server that responds with statusCodes in order (and 200 after that), and a counter of the
+// requests it received
+func newFlakyServer(t *testing.T, header http.Header, statusCodes ...int) (*httptest.Server, *atomic.Int32) {
+	var requestCount atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != "payload" {
+			// The body has to be sent again on every attempt
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		index := int(requestCount.Add(1)) - 1
+		if index >= len(statusCodes) {
+			io.WriteString(w, "ok")
+			return
+		}
+
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+
+		w.WriteHeader(statusCodes[index])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requestCount
+}
+
+func doRequest(t *testing.T, ctx context.Context, method string, url string) *http.Response {
+	request, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader("payload"))
+	require.NoError(t, err)
+
+	response, err := (&http.Client{Transport: &retryTransport{base: http.DefaultTransport}}).Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { response.Body.Close() })
+
+	return response
+}
+
+func TestRetryTransportRetriesIdempotentRequests(t *testing.T) {
+	server, requestCount := newFlakyServer(t, nil, http.StatusBadGateway, http.StatusGatewayTimeout)
+
+	response := doRequest(t, context.Background(), http.MethodGet, server.URL)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.EqualValues(t, 3, requestCount.Load())
+}
+
+func TestRetryTransportGivesUpAfterMaxAttempts(t *testing.T) {
+	server, requestCount := newFlakyServer(t, nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	response := doRequest(t, context.Background(), http.MethodGet, server.URL)
+	assert.Equal(t, http.StatusBadGateway, response.StatusCode)
+	assert.EqualValues(t, maxRequestAttempts, requestCount.Load())
+}
+
+func TestRetryTransportDoesntRetryPostOnBadGateway(t *testing.T) {
+	server, requestCount := newFlakyServer(t, nil, http.StatusBadGateway, http.StatusBadGateway)
+
+	response := doRequest(t, context.Background(), http.MethodPost, server.URL)
+	assert.Equal(t, http.StatusBadGateway, response.StatusCode)
+	assert.EqualValues(t, 1, requestCount.Load())
+
+	// Unless it's marked as safe to retry
+	response = doRequest(t, withRetries(context.Background()), http.MethodPost, server.URL)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.EqualValues(t, 3, requestCount.Load())
+}
+
+func TestRetryTransportRespectsRetryAfter(t *testing.T) {
+	server, requestCount := newFlakyServer(t, http.Header{"Retry-After": {"1"}}, http.StatusServiceUnavailable)
+
+	startedAt := time.Now()
+
+	response := doRequest(t, context.Background(), http.MethodGet, server.URL)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.EqualValues(t, 2, requestCount.Load())
+	assert.GreaterOrEqual(t, time.Since(startedAt), time.Second)
+}
+
+func TestRetryTransportDoesntRetryPostOnServiceUnavailable(t *testing.T) {
+	server, requestCount := newFlakyServer(t, nil, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	// The server might have processed part of the request, e.g. created a submission, before responding
+	response := doRequest(t, context.Background(), http.MethodPost, server.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.EqualValues(t, 1, requestCount.Load())
+
+	response = doRequest(t, withRetries(context.Background()), http.MethodPost, server.URL)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.EqualValues(t, 3, requestCount.Load())
+}
+
+func TestRetryTransportDoesntWaitForLongRetryAfter(t *testing.T) {
+	server, requestCount := newFlakyServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+
+	response := doRequest(t, context.Background(), http.MethodGet, server.URL)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.EqualValues(t, 1, requestCount.Load())
+}
+
+func TestRetryTransportStopsWhenContextIsCancelled(t *testing.T) {
+	server, _ := newFlakyServer(t, http.Header{"Retry-After": {"10"}}, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = (&http.Client{Transport: &retryTransport{base: http.DefaultTransport}}).Do(request)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("5")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := backoffDelay(attempt)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, min(retryBaseDelay<<(attempt-1), retryMaxDelay))
+	}
+}
@@ -1,8 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
 	"github.com/levigross/grequests"
 )
@@ -13,31 +12,37 @@ type UpdateBuildpackResponse struct {
 	IsError      bool          `json:"is_error"`
 }
 
-func (c CodecraftersClient) UpdateBuildpack(repositoryId string) (UpdateBuildpackResponse, error) {
-	response, err := grequests.Post(fmt.Sprintf("%s/services/cli/update_buildpack", c.ServerUrl), &grequests.RequestOptions{
-		JSON: map[string]interface{}{
-			"repository_id": repositoryId,
-		},
-		Headers: c.headers(),
-	})
+// UpdateBuildpack changes the repository's buildpack to buildpackSlug, or to the latest buildpack if it's empty
+func (c CodecraftersClient) UpdateBuildpack(ctx context.Context, repositoryId string, buildpackSlug string) (UpdateBuildpackResponse, error) {
+	operation, endpoint := "update buildpack", "/services/cli/update_buildpack"
+
+	requestJson := map[string]interface{}{
+		"repository_id": repositoryId,
+	}
 
-	if err != nil {
-		return UpdateBuildpackResponse{}, fmt.Errorf("failed to update buildpack: %s", err)
+	if buildpackSlug != "" {
+		requestJson["buildpack_slug"] = buildpackSlug
 	}
 
-	if !response.Ok {
-		return UpdateBuildpackResponse{}, fmt.Errorf("failed to update buildpack. status code: %d", response.StatusCode)
+	response, err := grequests.Post(c.ServerUrl+endpoint, &grequests.RequestOptions{
+		JSON:       requestJson,
+		Headers:    c.headers(),
+		HTTPClient: apiClient,
+		Context:    ctx,
+	})
+
+	if err := checkResponse(operation, endpoint, response, err); err != nil {
+		return UpdateBuildpackResponse{}, err
 	}
 
 	updateBuildpackResponse := UpdateBuildpackResponse{}
 
-	err = json.Unmarshal(response.Bytes(), &updateBuildpackResponse)
-	if err != nil {
-		return UpdateBuildpackResponse{}, fmt.Errorf("failed to parse update buildpack response: %s", err)
+	if err := parseResponse(operation, endpoint, response, &updateBuildpackResponse); err != nil {
+		return UpdateBuildpackResponse{}, err
 	}
 
 	if updateBuildpackResponse.IsError {
-		return updateBuildpackResponse, fmt.Errorf("%s", updateBuildpackResponse.ErrorMessage)
+		return updateBuildpackResponse, serverError(operation, endpoint, response, updateBuildpackResponse.ErrorMessage)
 	}
 
 	return updateBuildpackResponse, nil
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/actions"
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/history"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+// AttachCommand resumes streaming logs and waiting for the result of a submission, for example after the CLI was
+// interrupted. If submissionId is empty, the most recent submission in the local history is used.
+//
+// The submission's actions are fetched from the server, so submissions made from another checkout can be attached to
+// too.
+func AttachCommand(ctx context.Context, submissionId string) (err error) {
+	utils.Logger.Debug().Msg("attach command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("attach command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		var exitErr actions.ExitError
+		if errors.As(err, &exitErr) {
+			// The server asked to exit, e.g. because tests failed
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	store, err := history.OpenStore(codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		return err
+	}
+
+	records, err := store.Records()
+	if err != nil {
+		return err
+	}
+
+	// The history is only used to pick the submission (and to record its result), it doesn't need to have it
+	var record history.Record
+	var isRecorded bool
+
+	if submissionId == "" {
+		if len(records) == 0 {
+			return fmt.Errorf("No submissions found. Submissions are recorded when you run `codecrafters test` or `codecrafters submit`, pass a submission id to attach to another one.")
+		}
+
+		record, isRecorded = records[0], true
+	} else {
+		record, err = findHistoryRecord(records, submissionId)
+
+		switch {
+		case err == nil:
+			isRecorded = true
+		case slices.ContainsFunc(records, func(r history.Record) bool { return strings.HasPrefix(r.SubmissionId, submissionId) }):
+			// Ambiguous prefix
+			return err
+		default:
+			// Not made from this checkout, the id has to be complete
+			record = history.Record{SubmissionId: submissionId}
+		}
+	}
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	utils.Logger.Debug().Msgf("fetching actions for submission %s", record.SubmissionId)
+
+	fetchSubmissionActionsResponse, err := codecraftersClient.FetchSubmissionActions(ctx, record.SubmissionId)
+	if err != nil {
+		return fmt.Errorf("fetch submission actions: %w", err)
+	}
+
+	utils.Logger.Debug().Msgf("attaching to submission %s (status: %s)", record.SubmissionId, fetchSubmissionActionsResponse.Status)
+
+	fmt.Printf("Attaching to submission %s (commit: %s)...\n\n", record.SubmissionId, shortCommitSha(fetchSubmissionActionsResponse.CommitSHA))
+
+	// Only in-flight submissions need their final status recorded, finished ones already have it
+	if isRecorded && record.Status == "evaluating" {
+		trackSubmissionStatus(store, record)
+	}
+
+	return handleSubmission(ctx, client.CreateSubmissionResponse{
+		Id:        record.SubmissionId,
+		Actions:   fetchSubmissionActionsResponse.Actions,
+		CommitSHA: fetchSubmissionActionsResponse.CommitSHA,
+	}, codecraftersClient)
+}
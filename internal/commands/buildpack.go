@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/output"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+// repositoryBuildpacks is a repository's current buildpack, and the buildpacks it can be changed to
+type repositoryBuildpacks struct {
+	repoDir            string
+	repositoryId       string
+	codecraftersClient client.CodecraftersClient
+
+	currentBuildpackSlug string
+	buildpacks           []client.BuildpackInfo
+}
+
+func (r repositoryBuildpacks) latestBuildpack() client.BuildpackInfo {
+	for _, buildpack := range r.buildpacks {
+		if buildpack.IsLatest {
+			return buildpack
+		}
+	}
+
+	return client.BuildpackInfo{}
+}
+
+func (r repositoryBuildpacks) findBuildpack(slug string) (client.BuildpackInfo, bool) {
+	for _, buildpack := range r.buildpacks {
+		if buildpack.Slug == slug {
+			return buildpack, true
+		}
+	}
+
+	return client.BuildpackInfo{}, false
+}
+
+// BuildpackListCommand lists the buildpacks available for this repository, marking the current one
+func BuildpackListCommand(ctx context.Context) (err error) {
+	utils.Logger.Debug().Msg("buildpack list command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("buildpack list command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	buildpacks, err := fetchRepositoryBuildpacks(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, buildpack := range buildpacks.buildpacks {
+		marker := "  "
+		if buildpack.Slug == buildpacks.currentBuildpackSlug {
+			marker = "→ "
+		}
+
+		fmt.Fprintf(writer, "%s%s", marker, buildpack.Slug)
+
+		if buildpack.IsLatest {
+			fmt.Fprint(writer, "\t(latest)")
+		}
+
+		fmt.Fprintln(writer)
+	}
+
+	writer.Flush()
+
+	if _, ok := buildpacks.findBuildpack(buildpacks.currentBuildpackSlug); !ok {
+		fmt.Printf("\nCurrent buildpack: %s (no longer available)\n", buildpacks.currentBuildpackSlug)
+	}
+
+	fmt.Println("\nRun `codecrafters buildpack set <slug>` to switch to another buildpack.")
+
+	return nil
+}
+
+// BuildpackSetCommand changes this repository's buildpack to any of the available ones, including older ones
+func BuildpackSetCommand(ctx context.Context, buildpackSlug string, assumeYes bool) (err error) {
+	utils.Logger.Debug().Msg("buildpack set command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("buildpack set command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	buildpacks, err := fetchRepositoryBuildpacks(ctx)
+	if err != nil {
+		return err
+	}
+
+	buildpack, ok := buildpacks.findBuildpack(buildpackSlug)
+	if !ok {
+		return fmt.Errorf("Unknown buildpack '%s'. Run `codecrafters buildpack list` to see available buildpacks.", buildpackSlug)
+	}
+
+	if buildpack.Slug == buildpacks.currentBuildpackSlug {
+		output.Printf("Buildpack is already set to %s\n", buildpack.Slug)
+		return nil
+	}
+
+	output.Printf("Current buildpack: %s\n", buildpacks.currentBuildpackSlug)
+
+	if !buildpack.IsLatest {
+		output.Printf("Note: %s isn't the latest buildpack (%s).\n", buildpack.Slug, buildpacks.latestBuildpack().Slug)
+	}
+
+	return buildpacks.changeBuildpack(ctx, buildpack.Slug, fmt.Sprintf("Do you want to switch to %s?", buildpack.Slug), assumeYes)
+}
+
+func fetchRepositoryBuildpacks(ctx context.Context) (repositoryBuildpacks, error) {
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return repositoryBuildpacks{}, err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return repositoryBuildpacks{}, err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	utils.Logger.Debug().Msg("fetching current buildpack from server")
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	repositoryBuildpackResponse, err := codecraftersClient.FetchRepositoryBuildpack(ctx, codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to fetch repository buildpack")
+		return repositoryBuildpacks{}, fmt.Errorf("failed to fetch repository buildpack: %w", err)
+	}
+
+	utils.Logger.Debug().Msg("fetching available buildpacks from server")
+
+	buildpacksResponse, err := codecraftersClient.FetchBuildpacks(ctx, codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to fetch buildpacks")
+		return repositoryBuildpacks{}, fmt.Errorf("failed to fetch buildpacks: %w", err)
+	}
+
+	return repositoryBuildpacks{
+		repoDir:              repoDir,
+		repositoryId:         codecraftersRemote.CodecraftersRepositoryId(),
+		codecraftersClient:   codecraftersClient,
+		currentBuildpackSlug: repositoryBuildpackResponse.Buildpack.Slug,
+		buildpacks:           buildpacksResponse.Buildpacks,
+	}, nil
+}
+
+// changeBuildpack asks question to confirm, then updates the buildpack on the server and in codecrafters.yml
+func (r repositoryBuildpacks) changeBuildpack(ctx context.Context, buildpackSlug string, question string, assumeYes bool) error {
+	confirmed, err := confirm(question, assumeYes)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return fmt.Errorf("Aborted, the buildpack wasn't changed.")
+	}
+
+	utils.Logger.Debug().Msg("calling update buildpack API")
+
+	updateResponse, err := r.codecraftersClient.UpdateBuildpack(ctx, r.repositoryId, buildpackSlug)
+	if err != nil {
+		return fmt.Errorf("failed to update buildpack: %w", err)
+	}
+
+	if err := updateCodecraftersYmlBuildpack(r.repoDir, updateResponse.Buildpack.Slug); err != nil {
+		return err
+	}
+
+	output.Emit("buildpack_updated", map[string]interface{}{"previous_buildpack_slug": r.currentBuildpackSlug, "buildpack_slug": updateResponse.Buildpack.Slug})
+	output.Printf("Updated buildpack from %s to %s\n", r.currentBuildpackSlug, updateResponse.Buildpack.Slug)
+	return nil
+}
+
+func updateCodecraftersYmlBuildpack(repoDir string, buildpackSlug string) error {
+	utils.Logger.Debug().Msg("reading and updating codecrafters.yml file")
+
+	codecraftersYmlPath := filepath.Join(repoDir, "codecrafters.yml")
+
+	content, err := os.ReadFile(codecraftersYmlPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("codecrafters.yml file not found in repository root")
+		}
+		return fmt.Errorf("failed to read codecrafters.yml: %w", err)
+	}
+
+	updatedContent := utils.ReplaceYAMLField(string(content), "language_pack", "buildpack")
+	updatedContent = utils.ReplaceYAMLFieldValue(updatedContent, "buildpack", buildpackSlug)
+
+	err = os.WriteFile(codecraftersYmlPath, []byte(updatedContent), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write updated codecrafters.yml: %w", err)
+	}
+
+	return nil
+}
@@ -1,15 +1,23 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
+	"time"
 
 	"github.com/codecrafters-io/cli/internal/actions"
 	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/config"
+	"github.com/codecrafters-io/cli/internal/history"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 )
 
-func handleSubmission(createSubmissionResponse client.CreateSubmissionResponse, codecraftersClient client.CodecraftersClient) (err error) {
+func handleSubmission(ctx context.Context, createSubmissionResponse client.CreateSubmissionResponse, codecraftersClient client.CodecraftersClient) (err error) {
 	utils.Logger.Debug().Msgf("Handling submission with %d actions", len(createSubmissionResponse.Actions))
 
 	// Convert action definitions to concrete actions
@@ -26,7 +34,7 @@ func handleSubmission(createSubmissionResponse client.CreateSubmissionResponse,
 	for i, action := range actionsToExecute {
 		utils.Logger.Debug().Msgf("Executing %s (%d/%d)", reflect.TypeOf(action).String(), i+1, len(actionsToExecute))
 
-		if err := action.Execute(); err != nil {
+		if err := action.Execute(ctx); err != nil {
 			return fmt.Errorf("failed to execute action: %w", err)
 		}
 
@@ -35,3 +43,92 @@ func handleSubmission(createSubmissionResponse client.CreateSubmissionResponse,
 
 	return nil
 }
+
+// recordSubmission adds a submission to the local history, and updates it once the submission reaches a terminal
+// status. History is best-effort, failures are logged and otherwise ignored.
+func recordSubmission(repositoryId string, record history.Record) {
+	store, err := history.OpenStore(repositoryId)
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to open history store")
+		return
+	}
+
+	if err := store.Append(record); err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to record submission")
+		return
+	}
+
+	trackSubmissionStatus(store, record)
+}
+
+// trackSubmissionStatus updates record in store once the submission reaches a terminal status
+func trackSubmissionStatus(store history.Store, record history.Record) {
+	actions.SetTerminalSubmissionStatusHandler(func(submissionId string, status string) {
+		if submissionId != record.SubmissionId {
+			return
+		}
+
+		finishedAt := time.Now()
+		record.Status = status
+		record.FinishedAt = &finishedAt
+
+		if err := store.Append(record); err != nil {
+			utils.Logger.Debug().Err(err).Msg("failed to record submission status")
+		}
+	})
+}
+
+// codecraftersServerURL returns the server to use for a remote, honoring the server_url setting
+func codecraftersServerURL(codecraftersRemote utils.GitRemote) string {
+	if serverUrl := config.Get("server_url"); serverUrl != "" {
+		return serverUrl
+	}
+
+	return codecraftersRemote.CodecraftersServerURL()
+}
+
+// confirm asks question, unless --yes was passed (assumeYes) or the assume_yes setting is on
+func confirm(question string, assumeYes bool) (bool, error) {
+	return utils.Confirm(question, assumeYes || config.GetBool("assume_yes"), promptOutput())
+}
+
+// promptOutput returns where prompts are written: stdout, unless it's reserved for JSON events (--output json)
+func promptOutput() io.Writer {
+	if output.IsJSON() {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// identifyGitRemote is like utils.IdentifyGitRemote, but lets the user pick one on a terminal when there are several
+// CodeCrafters remotes. The choice is saved in the repository's config, so that it's only asked once.
+func identifyGitRemote(repoDir string) (utils.GitRemote, error) {
+	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+
+	var multipleRemotesErr utils.MultipleCodecraftersRemotesFoundError
+	if !errors.As(err, &multipleRemotesErr) || !utils.StdinIsTerminal() || output.IsJSON() {
+		return codecraftersRemote, err
+	}
+
+	options := []string{}
+	for _, remote := range multipleRemotesErr.Remotes {
+		options = append(options, fmt.Sprintf("%s (%s): %s", remote.Name, remote.Environment(), remote.Url))
+	}
+
+	index, err := utils.Choose("This repository has multiple CodeCrafters remotes. Which one do you want to use?", options, promptOutput())
+	if err != nil {
+		return utils.GitRemote{}, err
+	}
+
+	codecraftersRemote = multipleRemotesErr.Remotes[index]
+
+	if err := config.Current().Set("remote", codecraftersRemote.Name, true); err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to save remote")
+		fmt.Println("")
+	} else {
+		fmt.Printf("\nSaved %s as this repository's remote. Run `codecrafters config set --local remote <name>` to change it.\n\n", codecraftersRemote.Name)
+	}
+
+	return codecraftersRemote, nil
+}
@@ -0,0 +1,606 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/config"
+	"github.com/codecrafters-io/cli/internal/utils"
+)
+
+type completionFlag struct {
+	Name        string
+	Description string
+
+	// TakesValue is false for boolean flags. Values are completed from Values, the stage list or files if set.
+	TakesValue      bool
+	Values          []string
+	CompletesStages bool
+	CompletesFiles  bool
+}
+
+// dashedName returns the flag as it's usually typed, e.g. -m or --message
+func (f completionFlag) dashedName() string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+
+	return "--" + f.Name
+}
+
+type completionCommand struct {
+	Name        string
+	Description string
+	Flags       []completionFlag
+
+	// Subcommands are completed right after the command, Arguments right after the subcommand
+	Subcommands []string
+	Arguments   []string
+}
+
+// completionCommands describes the commands and flags parsed in run() in cmd/codecrafters/main.go, keep them in sync
+// (TestCompletionMatchesFlags in cmd/codecrafters checks this)
+var completionCommands = []completionCommand{
+	{
+		Name:        "submit",
+		Description: "Commit changes & run tests",
+		Flags: []completionFlag{
+			{Name: "m", Description: "Commit changes & run tests with a custom commit message", TakesValue: true},
+			{Name: "message", Description: "Commit changes & run tests with a custom commit message", TakesValue: true},
+			{Name: "dry-run", Description: "Show the changes that would be committed, without committing them"},
+		},
+	},
+	{
+		Name:        "test",
+		Description: "Run tests without committing changes",
+		Flags: []completionFlag{
+			{Name: "previous", Description: "Run tests for all previous stages and the current stage"},
+			{Name: "watch", Description: "Re-run tests whenever a file in the repository changes"},
+			{Name: "stage", Description: "Run tests for a specific stage (slug, +N, or -N)", TakesValue: true, CompletesStages: true},
+			{Name: "stages", Description: "Run tests for a range of stages", TakesValue: true, CompletesStages: true},
+			{Name: "local", Description: "Run tests locally using the tester passed in --tester"},
+			{Name: "tester", Description: "Path to a locally installed tester executable", TakesValue: true, CompletesFiles: true},
+			{Name: "dry-run", Description: "Show the changes that would be pushed, without pushing them"},
+		},
+	},
+	{
+		Name:        "task",
+		Description: "View current stage instructions",
+		Flags: []completionFlag{
+			{Name: "stage", Description: "View instructions for a specific stage (slug, +N, or -N)", TakesValue: true, CompletesStages: true},
+			{Name: "raw", Description: "Print instructions without pretty-printing"},
+			{Name: "export", Description: "Write instructions for all stages to this directory", TakesValue: true, CompletesFiles: true},
+			{Name: "format", Description: "Format of exported instructions", TakesValue: true, Values: []string{"md", "html"}},
+		},
+	},
+	{
+		Name:        "stages",
+		Description: "List all stages and your progress",
+		Flags: []completionFlag{
+			{Name: "format", Description: "Output format", TakesValue: true, Values: []string{"table", "plain", "json"}},
+		},
+	},
+	{
+		Name:        "ui",
+		Description: "Browse stages and run tests in a full-screen interface",
+	},
+	{
+		Name:        "update-buildpack",
+		Description: "Update language version",
+		Flags: []completionFlag{
+			{Name: "yes", Description: "Upgrade without asking for confirmation"},
+		},
+	},
+	{
+		Name:        "buildpack",
+		Description: "List language versions, or switch to a specific one",
+		Flags: []completionFlag{
+			{Name: "yes", Description: "Switch without asking for confirmation"},
+		},
+		Subcommands: []string{"list", "set"},
+	},
+	{
+		Name:        "status",
+		Description: "Show the state of this repository",
+		Flags: []completionFlag{
+			{Name: "json", Description: "Print status as JSON"},
+		},
+	},
+	{
+		Name:        "history",
+		Description: "List past test runs & submissions",
+		Flags: []completionFlag{
+			{Name: "command", Description: "Only show submissions created by this command", TakesValue: true, Values: []string{"test", "submit"}},
+			{Name: "status", Description: "Only show submissions with this status", TakesValue: true, Values: []string{"success", "failure", "evaluating"}},
+			{Name: "limit", Description: "Maximum number of submissions to show (0 for all)", TakesValue: true},
+			{Name: "json", Description: "Print history as JSON"},
+		},
+		Subcommands: []string{"show"},
+	},
+	{
+		Name:        "attach",
+		Description: "Resume streaming logs of an interrupted test run or submission",
+	},
+	{
+		Name:        "ping",
+		Description: "Test the connection to a CodeCrafters repository",
+	},
+	{
+		Name:        "doctor",
+		Description: "Diagnose common problems with your setup",
+	},
+	{
+		Name:        "config",
+		Description: "View and change CLI settings",
+		Flags: []completionFlag{
+			{Name: "local", Description: "Change the current repository's settings instead of the global ones"},
+		},
+		Subcommands: []string{"list", "get", "set", "unset"},
+		Arguments:   configKeys(),
+	},
+	{
+		Name:        "upgrade",
+		Description: "Upgrade to the latest version of the CLI",
+		Flags: []completionFlag{
+			{Name: "yes", Description: "Upgrade without asking for confirmation"},
+		},
+	},
+	{
+		Name:        "completion",
+		Description: "Print a shell completion script",
+		Subcommands: []string{"bash", "zsh", "fish"},
+	},
+	{
+		Name:        "help",
+		Description: "Show usage instructions",
+	},
+}
+
+var completionGlobalFlags = []completionFlag{
+	{Name: "help", Description: "Show usage instructions"},
+	{Name: "version", Description: "Print version and exit"},
+	{Name: "output", Description: "Output format", TakesValue: true, Values: []string{"text", "json"}},
+	{Name: "C", Description: "Use the repository at this directory", TakesValue: true, CompletesFiles: true},
+	{Name: "repo-dir", Description: "Use the repository at this directory", TakesValue: true, CompletesFiles: true},
+	{Name: "remote", Description: "Git remote to use when there are several CodeCrafters remotes", TakesValue: true},
+	{Name: "server", Description: "CodeCrafters server to use (a name from the config, or a URL)", TakesValue: true},
+}
+
+// completeStagesCommandName is a hidden command used by completion scripts to list stage slugs
+const completeStagesCommandName = "__complete-stages"
+
+func configKeys() []string {
+	keys := []string{}
+	for _, setting := range config.Settings {
+		keys = append(keys, setting.Key)
+	}
+
+	return keys
+}
+
+// CompletionCommand prints the completion script for shell
+func CompletionCommand(shell string) error {
+	switch shell {
+	case "bash":
+		fmt.Print(bashCompletionScript())
+	case "zsh":
+		fmt.Print(zshCompletionScript())
+	case "fish":
+		fmt.Print(fishCompletionScript())
+	default:
+		return fmt.Errorf("Usage: codecrafters completion <bash|zsh|fish>")
+	}
+
+	return nil
+}
+
+// CompleteStagesCommand prints the slugs of the repository's stages, one per line. It only reads the cached stage list
+// so that completion stays fast and works offline, and prints nothing on errors so that they don't end up in the
+// user's command line.
+func CompleteStagesCommand() error {
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return nil
+	}
+
+	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	if err != nil {
+		return nil
+	}
+
+	stageListResponse, err := loadCachedStageList(codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("no cached stage list to complete from")
+		return nil
+	}
+
+	for _, stage := range stageListResponse.Stages {
+		fmt.Println(stage.Slug)
+	}
+
+	return nil
+}
+
+func bashCompletionScript() string {
+	var script strings.Builder
+
+	script.WriteString(`# bash completion for codecrafters
+#
+# Load in the current shell with:
+#   source <(codecrafters completion bash)
+
+_codecrafters() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    # The command is the first word that isn't a global flag or its value
+    local i=1
+    while [[ ${i} -lt ${COMP_CWORD} ]]; do
+        case "${COMP_WORDS[i]}" in
+`)
+	fmt.Fprintf(&script, "        %s)\n", globalValueFlagPattern())
+	script.WriteString(`            # bash splits --flag=value into three words
+            if [[ "${COMP_WORDS[i+1]}" == "=" ]]; then
+                i=$((i + 3))
+            else
+                i=$((i + 2))
+            fi
+            ;;
+        -*)
+            i=$((i + 1))
+            ;;
+        *)
+            break
+            ;;
+        esac
+    done
+
+    if [[ ${i} -ge ${COMP_CWORD} ]]; then
+        case "${prev}" in
+`)
+	script.WriteString(bashFlagValueCases(completionGlobalFlags))
+	script.WriteString("        esac\n")
+	fmt.Fprintf(&script, "        COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(append(commandNames(), flagNames(completionGlobalFlags)...), " ")))
+	script.WriteString(`        return
+    fi
+
+    # Position of the word being completed, relative to the command
+    local position=$((COMP_CWORD - i))
+
+    case "${COMP_WORDS[i]}" in
+`)
+
+	for _, command := range completionCommands {
+		fmt.Fprintf(&script, "    %s)\n", command.Name)
+
+		valueCases := bashFlagValueCases(command.Flags)
+		if valueCases != "" {
+			script.WriteString("        case \"${prev}\" in\n")
+			script.WriteString(valueCases)
+			script.WriteString("        esac\n")
+		}
+
+		flagWords := flagNames(command.Flags)
+		if len(command.Subcommands) > 0 {
+			script.WriteString("        if [[ ${position} -eq 1 ]]; then\n")
+			fmt.Fprintf(&script, "            COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(append(command.Subcommands, flagWords...), " ")))
+			script.WriteString("            return\n")
+			script.WriteString("        fi\n")
+		}
+
+		if len(command.Arguments) > 0 {
+			script.WriteString("        if [[ ${position} -eq 2 ]]; then\n")
+			fmt.Fprintf(&script, "            COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(append(command.Arguments, flagWords...), " ")))
+			script.WriteString("            return\n")
+			script.WriteString("        fi\n")
+		}
+
+		fmt.Fprintf(&script, "        COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(flagWords, " ")))
+		script.WriteString("        ;;\n")
+	}
+
+	script.WriteString(`    esac
+}
+
+complete -F _codecrafters codecrafters
+`)
+
+	return script.String()
+}
+
+func bashFlagValueCases(flags []completionFlag) string {
+	var cases strings.Builder
+
+	for _, flag := range flags {
+		if !flag.TakesValue {
+			continue
+		}
+
+		// Go's flag package accepts both -name and --name
+		fmt.Fprintf(&cases, "        -%s|--%s)\n", flag.Name, flag.Name)
+
+		switch {
+		case flag.CompletesStages:
+			fmt.Fprintf(&cases, "            COMPREPLY=($(compgen -W \"$(codecrafters %s 2>/dev/null)\" -- \"${cur}\"))\n", completeStagesCommandName)
+		case flag.CompletesFiles:
+			cases.WriteString("            COMPREPLY=($(compgen -f -- \"${cur}\"))\n")
+		default:
+			fmt.Fprintf(&cases, "            COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(flag.Values, " ")))
+		}
+
+		cases.WriteString("            return\n")
+		cases.WriteString("            ;;\n")
+	}
+
+	return cases.String()
+}
+
+func zshCompletionScript() string {
+	var script strings.Builder
+
+	script.WriteString(`#compdef codecrafters
+#
+# Load in the current shell with:
+#   source <(codecrafters completion zsh)
+#
+# or save as _codecrafters in a directory on your $fpath.
+
+_codecrafters() {
+    # The command is the first word that isn't a global flag or its value
+    local i=2
+    while (( i < CURRENT )); do
+        case "${words[i]}" in
+`)
+	fmt.Fprintf(&script, "        %s)\n", globalValueFlagPattern())
+	script.WriteString(`            (( i += 2 ))
+            ;;
+        -*)
+            (( i += 1 ))
+            ;;
+        *)
+            break
+            ;;
+        esac
+    done
+
+    if (( i >= CURRENT )); then
+        case "${words[CURRENT-1]}" in
+`)
+	script.WriteString(zshFlagValueCases(completionGlobalFlags))
+	script.WriteString(`        esac
+
+        local -a commands
+        commands=(
+`)
+
+	for _, command := range completionCommands {
+		fmt.Fprintf(&script, "            %s\n", shellQuote(zshDescribeEntry(command.Name, command.Description)))
+	}
+
+	for _, flag := range completionGlobalFlags {
+		fmt.Fprintf(&script, "            %s\n", shellQuote(zshDescribeEntry(flag.dashedName(), flag.Description)))
+	}
+
+	script.WriteString(`        )
+        _describe 'command' commands
+        return
+    fi
+
+    # Position of the word being completed, relative to the command
+    local position=$(( CURRENT - i ))
+
+    case "${words[i]}" in
+`)
+
+	for _, command := range completionCommands {
+		fmt.Fprintf(&script, "    %s)\n", command.Name)
+
+		valueCases := zshFlagValueCases(command.Flags)
+		if valueCases != "" {
+			script.WriteString("        case \"${words[CURRENT-1]}\" in\n")
+			script.WriteString(valueCases)
+			script.WriteString("        esac\n")
+		}
+
+		if len(command.Subcommands) > 0 {
+			script.WriteString("        if (( position == 1 )); then\n")
+			fmt.Fprintf(&script, "            compadd -- %s\n", strings.Join(command.Subcommands, " "))
+			script.WriteString("        fi\n")
+		}
+
+		if len(command.Arguments) > 0 {
+			script.WriteString("        if (( position == 2 )); then\n")
+			fmt.Fprintf(&script, "            compadd -- %s\n", strings.Join(command.Arguments, " "))
+			script.WriteString("        fi\n")
+		}
+
+		if len(command.Flags) > 0 {
+			script.WriteString("        local -a flags\n")
+			script.WriteString("        flags=(\n")
+
+			for _, flag := range command.Flags {
+				fmt.Fprintf(&script, "            %s\n", shellQuote(zshDescribeEntry(flag.dashedName(), flag.Description)))
+			}
+
+			script.WriteString("        )\n")
+			script.WriteString("        _describe 'flag' flags\n")
+		}
+
+		script.WriteString("        ;;\n")
+	}
+
+	script.WriteString(`    esac
+}
+
+if [ "${funcstack[1]}" = "_codecrafters" ]; then
+    _codecrafters "$@"
+else
+    compdef _codecrafters codecrafters
+fi
+`)
+
+	return script.String()
+}
+
+func zshFlagValueCases(flags []completionFlag) string {
+	var cases strings.Builder
+
+	for _, flag := range flags {
+		if !flag.TakesValue {
+			continue
+		}
+
+		fmt.Fprintf(&cases, "        -%s|--%s)\n", flag.Name, flag.Name)
+
+		switch {
+		case flag.CompletesStages:
+			fmt.Fprintf(&cases, "            compadd -- ${(f)\"$(codecrafters %s 2>/dev/null)\"}\n", completeStagesCommandName)
+		case flag.CompletesFiles:
+			cases.WriteString("            _files\n")
+		case len(flag.Values) > 0:
+			fmt.Fprintf(&cases, "            compadd -- %s\n", strings.Join(flag.Values, " "))
+		default:
+			cases.WriteString("            _message 'value'\n")
+		}
+
+		cases.WriteString("            return\n")
+		cases.WriteString("            ;;\n")
+	}
+
+	return cases.String()
+}
+
+// zshDescribeEntry formats an entry for _describe, where colons in the name must be escaped
+func zshDescribeEntry(name string, description string) string {
+	return strings.ReplaceAll(name, ":", `\:`) + ":" + description
+}
+
+func fishCompletionScript() string {
+	var script strings.Builder
+
+	script.WriteString(`# fish completion for codecrafters
+#
+# Load in the current shell with:
+#   codecrafters completion fish | source
+
+complete -c codecrafters -f
+
+# Prints the command, the first word that isn't a global flag or its value. Fails if there's no command yet.
+function __codecrafters_command
+    set -l words (commandline -opc)
+    set -l i 2
+    while test $i -le (count $words)
+        switch $words[$i]
+`)
+	fmt.Fprintf(&script, "            case %s\n", strings.ReplaceAll(globalValueFlagPattern(), "|", " "))
+	script.WriteString(`                set i (math $i + 2)
+            case '-*'
+                set i (math $i + 1)
+            case '*'
+                echo $words[$i]
+                return 0
+        end
+    end
+
+    return 1
+end
+
+function __codecrafters_using_command
+    set -l command (__codecrafters_command); or return 1
+    contains -- $command $argv
+end
+
+`)
+
+	for _, command := range completionCommands {
+		fmt.Fprintf(&script, "complete -c codecrafters -n 'not __codecrafters_command' -a %s -d %s\n", command.Name, shellQuote(command.Description))
+	}
+
+	for _, flag := range completionGlobalFlags {
+		script.WriteString(fishFlagCompletion("not __codecrafters_command", flag))
+	}
+
+	for _, command := range completionCommands {
+		script.WriteString("\n")
+
+		condition := "__codecrafters_using_command " + command.Name
+
+		if len(command.Subcommands) > 0 {
+			subcommandCondition := shellQuote(fmt.Sprintf("%s; and not __fish_seen_subcommand_from %s", condition, strings.Join(command.Subcommands, " ")))
+			fmt.Fprintf(&script, "complete -c codecrafters -n %s -a %s\n", subcommandCondition, shellQuote(strings.Join(command.Subcommands, " ")))
+		}
+
+		if len(command.Arguments) > 0 {
+			argumentCondition := shellQuote(fmt.Sprintf("%s; and __fish_seen_subcommand_from %s", condition, strings.Join(command.Subcommands, " ")))
+			fmt.Fprintf(&script, "complete -c codecrafters -n %s -a %s\n", argumentCondition, shellQuote(strings.Join(command.Arguments, " ")))
+		}
+
+		for _, flag := range command.Flags {
+			script.WriteString(fishFlagCompletion(condition, flag))
+		}
+	}
+
+	return script.String()
+}
+
+// fishFlagCompletion returns the complete command for flag, and its value if it takes one
+func fishFlagCompletion(condition string, flag completionFlag) string {
+	line := fmt.Sprintf("complete -c codecrafters -n %s %s", shellQuote(condition), fishFlagOption(flag))
+
+	switch {
+	case flag.CompletesStages:
+		line += fmt.Sprintf(" -x -a %s", shellQuote(fmt.Sprintf("(codecrafters %s 2>/dev/null)", completeStagesCommandName)))
+	case flag.CompletesFiles:
+		line += " -r -F"
+	case len(flag.Values) > 0:
+		line += fmt.Sprintf(" -x -a %s", shellQuote(strings.Join(flag.Values, " ")))
+	case flag.TakesValue:
+		line += " -x"
+	}
+
+	return fmt.Sprintf("%s -d %s\n", line, shellQuote(flag.Description))
+}
+
+func fishFlagOption(flag completionFlag) string {
+	if len(flag.Name) == 1 {
+		return "-s " + flag.Name
+	}
+
+	return "-l " + flag.Name
+}
+
+// globalValueFlagPattern matches the global flags that take a value, in both their -name and --name forms, e.g.
+// "-C|--C|-remote|--remote"
+func globalValueFlagPattern() string {
+	patterns := []string{}
+	for _, flag := range completionGlobalFlags {
+		if flag.TakesValue {
+			patterns = append(patterns, "-"+flag.Name, "--"+flag.Name)
+		}
+	}
+
+	return strings.Join(patterns, "|")
+}
+
+func commandNames() []string {
+	names := []string{}
+	for _, command := range completionCommands {
+		names = append(names, command.Name)
+	}
+
+	return names
+}
+
+func flagNames(flags []completionFlag) []string {
+	names := []string{}
+	for _, flag := range flags {
+		names = append(names, flag.dashedName())
+	}
+
+	return names
+}
+
+// shellQuote wraps s in single quotes, which all supported shells treat literally
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// completeWithBash returns the completions bash's completion script offers for the last word of words
+func completeWithBash(t *testing.T, words ...string) []string {
+	scriptPath := filepath.Join(t.TempDir(), "codecrafters.bash")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(bashCompletionScript()), 0644))
+
+	quotedWords := []string{}
+	for _, word := range words {
+		quotedWords = append(quotedWords, shellQuote(word))
+	}
+
+	program := strings.Join([]string{
+		"source " + shellQuote(scriptPath),
+		"COMP_WORDS=(" + strings.Join(quotedWords, " ") + ")",
+		"COMP_CWORD=$(( ${#COMP_WORDS[@]} - 1 ))",
+		"_codecrafters",
+		`printf '%s\n' "${COMPREPLY[@]}"`,
+	}, "\n")
+
+	output, err := exec.Command("bash", "-c", program).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	return strings.Fields(string(output))
+}
+
+func TestBashCompletion(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash isn't installed")
+	}
+
+	assert.Contains(t, completeWithBash(t, "codecrafters", ""), "submit")
+	assert.Equal(t, []string{"text", "json"}, completeWithBash(t, "codecrafters", "--output", ""))
+	assert.Equal(t, []string{"--stage", "--stages"}, completeWithBash(t, "codecrafters", "test", "--st"))
+
+	t.Run("skips global flags before the command", func(t *testing.T) {
+		assert.Contains(t, completeWithBash(t, "codecrafters", "-C", "dir", "--server", "local", ""), "submit")
+		assert.Equal(t, []string{"--stage", "--stages"}, completeWithBash(t, "codecrafters", "-C", "dir", "test", "--st"))
+		assert.Equal(t, []string{"list", "get", "set", "unset", "--local"}, completeWithBash(t, "codecrafters", "--remote", "origin", "config", ""))
+		assert.Contains(t, completeWithBash(t, "codecrafters", "--output", "=", "json", "--help", "config", "set", ""), "submit.branch")
+	})
+}
+
+// completeWithZsh returns the completions zsh's completion script offers for the last word of words. zsh filters them by
+// what's been typed, so this returns all the candidates instead.
+func completeWithZsh(t *testing.T, words ...string) []string {
+	scriptPath := filepath.Join(t.TempDir(), "_codecrafters")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(zshCompletionScript()), 0644))
+
+	quotedWords := []string{}
+	for _, word := range words {
+		quotedWords = append(quotedWords, shellQuote(word))
+	}
+
+	// Outside of an interactive shell, the completion system isn't available. These stand-ins print the candidates.
+	program := strings.Join([]string{
+		"compdef() { :; }",
+		`_describe() { local entry; for entry in "${(@P)2}"; do print -r -- "${entry%%:*}"; done; }`,
+		`compadd() { while [[ $# -gt 0 && "$1" != "--" ]]; do shift; done; shift; print -rl -- "$@"; }`,
+		"_files() { print -r -- '<files>'; }",
+		"_message() { :; }",
+		"source " + shellQuote(scriptPath),
+		"words=(" + strings.Join(quotedWords, " ") + ")",
+		"CURRENT=${#words}",
+		"_codecrafters",
+	}, "\n")
+
+	output, err := exec.Command("zsh", "-f", "-c", program).CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	return strings.Fields(string(output))
+}
+
+func TestZshCompletion(t *testing.T) {
+	if _, err := exec.LookPath("zsh"); err != nil {
+		t.Skip("zsh isn't installed")
+	}
+
+	assert.Contains(t, completeWithZsh(t, "codecrafters", ""), "submit")
+	assert.Equal(t, []string{"text", "json"}, completeWithZsh(t, "codecrafters", "--output", ""))
+	assert.Contains(t, completeWithZsh(t, "codecrafters", "test", "--st"), "--stages")
+
+	t.Run("skips global flags before the command", func(t *testing.T) {
+		assert.Contains(t, completeWithZsh(t, "codecrafters", "-C", "dir", "--server", "local", ""), "submit")
+
+		candidates := completeWithZsh(t, "codecrafters", "-C", "dir", "test", "--st")
+		assert.Contains(t, candidates, "--stage")
+		assert.NotContains(t, candidates, "submit")
+
+		assert.Equal(t, []string{"list", "get", "set", "unset", "--local"}, completeWithZsh(t, "codecrafters", "--remote", "origin", "config", ""))
+		assert.Contains(t, completeWithZsh(t, "codecrafters", "--output", "json", "--help", "config", "set", ""), "submit.branch")
+	})
+}
+
+// completeWithFish returns the completions fish's completion script offers for the last word of words
+func completeWithFish(t *testing.T, words ...string) []string {
+	scriptPath := filepath.Join(t.TempDir(), "codecrafters.fish")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(fishCompletionScript()), 0644))
+
+	program := strings.Join([]string{
+		"source " + shellQuote(scriptPath),
+		"complete -C " + shellQuote(strings.Join(words, " ")),
+	}, "\n")
+
+	cmd := exec.Command("fish", "-c", program)
+	cmd.Env = append(os.Environ(), "XDG_CONFIG_HOME="+t.TempDir(), "XDG_DATA_HOME="+t.TempDir())
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	// Completions are printed as "<candidate>\t<description>"
+	candidates := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if candidate, _, _ := strings.Cut(line, "\t"); candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates
+}
+
+func TestFishCompletion(t *testing.T) {
+	if _, err := exec.LookPath("fish"); err != nil {
+		t.Skip("fish isn't installed")
+	}
+
+	assert.Contains(t, completeWithFish(t, "codecrafters", ""), "submit")
+	assert.ElementsMatch(t, []string{"text", "json"}, completeWithFish(t, "codecrafters", "--output", ""))
+	assert.ElementsMatch(t, []string{"--stage", "--stages"}, completeWithFish(t, "codecrafters", "test", "--st"))
+
+	t.Run("skips global flags before the command", func(t *testing.T) {
+		assert.Contains(t, completeWithFish(t, "codecrafters", "-C", "dir", "--server", "local", ""), "submit")
+		assert.ElementsMatch(t, []string{"--stage", "--stages"}, completeWithFish(t, "codecrafters", "-C", "dir", "test", "--st"))
+		assert.ElementsMatch(t, []string{"list", "get", "set", "unset"}, completeWithFish(t, "codecrafters", "--remote", "origin", "config", ""))
+		assert.Contains(t, completeWithFish(t, "codecrafters", "--output", "json", "--help", "config", "set", ""), "submit.branch")
+	})
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/codecrafters-io/cli/internal/config"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+const configUsage = `Usage:
+  codecrafters config list
+  codecrafters config get <key>
+  codecrafters config set [--local] <key> <value>
+  codecrafters config unset [--local] <key>`
+
+// ConfigCommand manages settings. Settings are written to the global config file, or the repository's config file if
+// isLocal is set.
+func ConfigCommand(subcommand string, args []string, isLocal bool) (err error) {
+	utils.Logger.Debug().Msg("config command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("config command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+	}()
+
+	currentConfig := config.Current()
+
+	switch {
+	case subcommand == "list" && len(args) == 0:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "KEY\tVALUE\tSOURCE\tDESCRIPTION")
+
+		for _, setting := range config.Settings {
+			value, source := currentConfig.Lookup(setting.Key)
+			if source == config.SourceEnv {
+				source = config.EnvVarName(setting.Key)
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", setting.Key, value, source, setting.Description)
+		}
+
+		writer.Flush()
+
+		printConfigSection("Servers (use with --server)", config.Servers())
+		printConfigSection("Git hosts", config.GitHosts())
+
+		fmt.Println("")
+		fmt.Printf("Global config: %s\n", currentConfig.GlobalPath)
+		if currentConfig.LocalPath != "" {
+			fmt.Printf("Local config:  %s\n", currentConfig.LocalPath)
+		}
+	case subcommand == "get" && len(args) == 1:
+		if err := config.CheckKey(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Println(config.Get(args[0]))
+	case subcommand == "set" && len(args) == 2:
+		if err := currentConfig.Set(args[0], args[1], isLocal); err != nil {
+			return err
+		}
+
+		if value, source := currentConfig.Lookup(args[0]); source == config.SourceEnv {
+			fmt.Printf("Note: %s is overridden by %s=%s\n", args[0], config.EnvVarName(args[0]), value)
+		}
+	case subcommand == "unset" && len(args) == 1:
+		return currentConfig.Unset(args[0], isLocal)
+	default:
+		return fmt.Errorf("%s", configUsage)
+	}
+
+	return nil
+}
+
+func printConfigSection(title string, entries map[string]string) {
+	if len(entries) == 0 {
+		return
+	}
+
+	names := []string{}
+	for name := range entries {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Println("")
+	fmt.Printf("%s:\n", title)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(writer, "  %s\t%s\n", name, entries[name])
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,328 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/fatih/color"
+	"github.com/getsentry/sentry-go"
+	"gopkg.in/yaml.v3"
+)
+
+// The oldest git version we expect the CLI to work with
+const minimumGitMajorVersion, minimumGitMinorVersion = 2, 20
+
+const doctorNetworkTimeout = 10 * time.Second
+
+// doctorReport prints the result of each check as it runs
+type doctorReport struct {
+	failedCheckCount int
+}
+
+func (r *doctorReport) pass(name string, detail string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s %s%s\n", green("✓"), name, formatDoctorDetail(detail))
+}
+
+func (r *doctorReport) fail(name string, detail string, hint string) {
+	red := color.New(color.FgRed).SprintFunc()
+	fmt.Printf("%s %s%s\n", red("✗"), name, formatDoctorDetail(detail))
+
+	for _, line := range strings.Split(hint, "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+
+	r.failedCheckCount++
+}
+
+func (r *doctorReport) skip(name string, reason string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Printf("%s %s (skipped: %s)\n", yellow("-"), name, reason)
+}
+
+func formatDoctorDetail(detail string) string {
+	if detail == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s)", detail)
+}
+
+func DoctorCommand(ctx context.Context) (err error) {
+	utils.Logger.Debug().Msg("doctor command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("doctor command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+	}()
+
+	report := &doctorReport{}
+
+	if checkGit(report) {
+		checkRepository(ctx, report)
+	}
+
+	fmt.Println("")
+
+	if err := ctx.Err(); err != nil {
+		// Checks that were interrupted are reported as failed, which isn't worth a summary
+		return err
+	}
+
+	if report.failedCheckCount > 0 {
+		return fmt.Errorf("%d check(s) failed. If you need help, let us know at hello@codecrafters.io.", report.failedCheckCount)
+	}
+
+	fmt.Println("All checks passed.")
+
+	return nil
+}
+
+// checkGit returns false if git isn't usable, in which case there's no point running further checks
+func checkGit(report *doctorReport) bool {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		report.fail("Git is installed", "", "Install git from https://git-scm.com/downloads and make sure it's on your PATH.")
+		return false
+	}
+
+	report.pass("Git is installed", gitPath)
+
+	outputBytes, err := exec.Command("git", "--version").CombinedOutput()
+	versionOutput := strings.TrimSpace(string(outputBytes))
+	if err != nil {
+		report.fail("Git version is supported", versionOutput, "Check that your git installation works by running `git --version`.")
+		return false
+	}
+
+	minimumVersion := fmt.Sprintf("%d.%d", minimumGitMajorVersion, minimumGitMinorVersion)
+	if isSupportedGitVersion(versionOutput) {
+		report.pass("Git version is supported", versionOutput)
+	} else {
+		report.fail("Git version is supported", versionOutput, fmt.Sprintf("Upgrade git to version %s or later.", minimumVersion))
+	}
+
+	userName, _ := exec.Command("git", "config", "user.name").Output()
+	userEmail, _ := exec.Command("git", "config", "user.email").Output()
+
+	if strings.TrimSpace(string(userName)) == "" || strings.TrimSpace(string(userEmail)) == "" {
+		report.fail("Git author identity is configured", "", "Run:\n  git config --global user.name \"Your Name\"\n  git config --global user.email \"you@example.com\"")
+	} else {
+		report.pass("Git author identity is configured", fmt.Sprintf("%s <%s>", strings.TrimSpace(string(userName)), strings.TrimSpace(string(userEmail))))
+	}
+
+	return true
+}
+
+func isSupportedGitVersion(versionOutput string) bool {
+	matches := regexp.MustCompile(`(\d+)\.(\d+)`).FindStringSubmatch(versionOutput)
+	if matches == nil {
+		return false
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+
+	return major > minimumGitMajorVersion || (major == minimumGitMajorVersion && minor >= minimumGitMinorVersion)
+}
+
+func checkRepository(ctx context.Context, report *doctorReport) {
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		report.fail("Current directory is a git repository", "", err.Error())
+		report.skip("CodeCrafters remote is configured", "no repository")
+		return
+	}
+
+	report.pass("Current directory is a git repository", repoDir)
+
+	checkGitIgnore(report)
+	checkCodecraftersYml(report, repoDir)
+
+	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	if err != nil {
+		var noRemoteErr utils.NoCodecraftersRemoteFoundError
+		var multipleRemotesErr utils.MultipleCodecraftersRemotesFoundError
+		var remoteNotFoundErr utils.GitRemoteNotFoundError
+
+		switch {
+		case errors.As(err, &noRemoteErr):
+			report.fail("CodeCrafters remote is configured", "", "Run this command from the repository you cloned from CodeCrafters, or add the\nCodeCrafters remote shown on your repository's page with `git remote add origin <url>`.")
+		case errors.As(err, &multipleRemotesErr):
+			report.fail("CodeCrafters remote is configured", err.Error(), "Choose one with `codecrafters config set --local remote <name>`, or remove the extra\nremotes with `git remote remove <name>` so that only one is left.")
+		case errors.As(err, &remoteNotFoundErr):
+			report.fail("CodeCrafters remote is configured", err.Error(), "Change the remote to use with `codecrafters config set --local remote <name>`, or pass --remote.")
+		default:
+			report.fail("CodeCrafters remote is configured", err.Error(), "Check that `git remote -v` works in this repository.")
+		}
+
+		report.skip("CodeCrafters server is reachable", "no remote")
+		report.skip("Git remote is accessible", "no remote")
+		return
+	}
+
+	report.pass("CodeCrafters remote is configured", fmt.Sprintf("%s: %s", codecraftersRemote.Name, codecraftersRemote.Url))
+
+	checkServerReachability(ctx, report, codecraftersServerURL(codecraftersRemote))
+	checkGitRemoteAccess(ctx, report, repoDir, codecraftersRemote)
+}
+
+func checkGitIgnore(report *doctorReport) {
+	globalGitIgnorePath := utils.GetGlobalGitIgnorePath()
+	if globalGitIgnorePath == "" {
+		report.pass("Global gitignore resolves", "core.excludesfile is not set")
+		return
+	}
+
+	if _, err := os.Stat(globalGitIgnorePath); err != nil {
+		report.fail("Global gitignore resolves", globalGitIgnorePath, "core.excludesfile points to a file that can't be read. Fix the path with\n`git config --global core.excludesfile <path>`, or unset it.")
+		return
+	}
+
+	report.pass("Global gitignore resolves", globalGitIgnorePath)
+}
+
+func checkCodecraftersYml(report *doctorReport, repoDir string) {
+	content, err := os.ReadFile(filepath.Join(repoDir, "codecrafters.yml"))
+	if err != nil {
+		report.fail("codecrafters.yml is valid", "", "codecrafters.yml is missing from the repository root. Restore it with\n`git checkout HEAD -- codecrafters.yml`.")
+		return
+	}
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		report.fail("codecrafters.yml is valid", err.Error(), "Fix the syntax error in codecrafters.yml.")
+		return
+	}
+
+	report.pass("codecrafters.yml is valid", "")
+}
+
+func checkServerReachability(ctx context.Context, report *doctorReport, serverUrl string) {
+	parsedUrl, err := url.Parse(serverUrl)
+	if err != nil || parsedUrl.Hostname() == "" {
+		report.fail("CodeCrafters server is reachable", serverUrl, "The server URL couldn't be parsed, check your git remote's URL.")
+		return
+	}
+
+	host := parsedUrl.Hostname()
+	port := parsedUrl.Port()
+	if port == "" {
+		port = "443"
+		if parsedUrl.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorNetworkTimeout)
+	defer cancel()
+
+	proxyUrl, err := client.ProxyURL(parsedUrl)
+	if err != nil {
+		report.fail("Proxy settings are valid", err.Error(), "Check the HTTPS_PROXY and HTTP_PROXY environment variables.")
+		return
+	}
+
+	if proxyUrl != nil {
+		// The proxy resolves the host. Redacted, so that credentials aren't printed.
+		report.pass("Using proxy", proxyUrl.Redacted())
+	} else {
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			report.fail("DNS resolves "+host, err.Error(), "Check your internet connection and DNS settings.")
+			report.skip("TLS connection to "+host, "DNS failed")
+			report.skip("HTTP request to "+serverUrl, "DNS failed")
+			return
+		}
+
+		report.pass("DNS resolves "+host, strings.Join(addresses, ", "))
+	}
+
+	if parsedUrl.Scheme == "https" {
+		conn, err := dialTLS(ctx, host, port)
+		if err != nil {
+			report.fail("TLS connection to "+host, err.Error(), "A firewall or proxy might be blocking or intercepting HTTPS connections. If your network uses a\nTLS-intercepting proxy, set CODECRAFTERS_CA_BUNDLE to the path of its root certificate.")
+			report.skip("HTTP request to "+serverUrl, "TLS failed")
+			return
+		}
+
+		conn.Close()
+		report.pass("TLS connection to "+host, "")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, serverUrl, nil)
+	if err != nil {
+		report.fail("HTTP request to "+serverUrl, err.Error(), "The server URL couldn't be parsed, check your git remote's URL.")
+		return
+	}
+
+	response, err := client.HTTPClient().Do(request)
+	if err != nil {
+		report.fail("HTTP request to "+serverUrl, err.Error(), "Check your internet connection and proxy settings.")
+		return
+	}
+
+	response.Body.Close()
+
+	// Any response means the server is reachable, the root path doesn't need to return a 2xx
+	report.pass("HTTP request to "+serverUrl, response.Status)
+}
+
+// dialTLS connects to host the same way requests to CodeCrafters do, i.e. through the proxy and with the CA bundle
+func dialTLS(ctx context.Context, host string, port string) (net.Conn, error) {
+	conn, err := client.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, client.TLSConfig(host))
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func checkGitRemoteAccess(ctx context.Context, report *doctorReport, repoDir string, codecraftersRemote utils.GitRemote) {
+	ctx, cancel := context.WithTimeout(ctx, 3*doctorNetworkTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoDir, "ls-remote", codecraftersRemote.Name, "HEAD")
+	// Fail instead of waiting for credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	outputBytes, err := cmd.CombinedOutput()
+	if err != nil {
+		detail := strings.TrimSpace(string(outputBytes))
+		if ctx.Err() != nil {
+			detail = "timed out"
+		}
+
+		report.fail("Git remote is accessible", detail, fmt.Sprintf("Check that you can run `git ls-remote %s` in this repository.", codecraftersRemote.Name))
+		return
+	}
+
+	report.pass("Git remote is accessible", "")
+}
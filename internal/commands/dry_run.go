@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/codecrafters-io/cli/internal/output"
+)
+
+type fileToCommit struct {
+	Path   string
+	Status string // As reported by `git diff --name-status`: A, M, D, T etc.
+	Size   int64
+}
+
+// listFilesToCommit lists the changes that commitChanges would stage in repoDir. A temporary copy of the git index is
+// used, so the repository's own index isn't modified.
+func listFilesToCommit(repoDir string) ([]fileToCommit, error) {
+	outputBytes, err := exec.Command("git", "-C", repoDir, "rev-parse", "--git-path", "index").CombinedOutput()
+	if err != nil {
+		return nil, wrapError(err, outputBytes, "find git index")
+	}
+
+	// This is relative to repoDir unless the git dir is elsewhere (e.g. in worktrees)
+	indexPath := strings.TrimSpace(string(outputBytes))
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(repoDir, indexPath)
+	}
+
+	tmpIndexFile, err := os.CreateTemp("", "codecrafters-index")
+	if err != nil {
+		return nil, fmt.Errorf("create temp index: %w", err)
+	}
+
+	tmpIndexFile.Close()
+	defer os.Remove(tmpIndexFile.Name())
+
+	indexContent, err := os.ReadFile(indexPath)
+	if err == nil {
+		if err := os.WriteFile(tmpIndexFile.Name(), indexContent, 0644); err != nil {
+			return nil, fmt.Errorf("copy git index: %w", err)
+		}
+	} else if os.IsNotExist(err) {
+		// Git treats an empty index file as corrupt, a missing one is fine
+		os.Remove(tmpIndexFile.Name())
+	} else {
+		return nil, fmt.Errorf("read git index: %w", err)
+	}
+
+	gitEnv := append(os.Environ(), "GIT_INDEX_FILE="+tmpIndexFile.Name())
+
+	addCmd := exec.Command("git", "-C", repoDir, "add", ".")
+	addCmd.Env = gitEnv
+	outputBytes, err = addCmd.CombinedOutput()
+	if err != nil {
+		return nil, wrapError(err, outputBytes, "add all files")
+	}
+
+	diffCmd := exec.Command("git", "-C", repoDir, "diff", "--cached", "--name-status", "--no-renames", "-z")
+	diffCmd.Env = gitEnv
+	outputBytes, err = diffCmd.Output()
+	if err != nil {
+		return nil, wrapError(err, outputBytes, "list staged files")
+	}
+
+	// With -z, output is a sequence of NUL-terminated status and path pairs
+	fields := bytes.Split(bytes.TrimSuffix(outputBytes, []byte{0}), []byte{0})
+	files := []fileToCommit{}
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		file := fileToCommit{
+			Status: string(fields[i]),
+			Path:   string(fields[i+1]),
+		}
+
+		if file.Status != "D" {
+			if info, err := os.Lstat(filepath.Join(repoDir, file.Path)); err == nil {
+				file.Size = info.Size()
+			}
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func printDryRun(files []fileToCommit, commitMessage string) {
+	var totalSize int64
+	for _, file := range files {
+		totalSize += file.Size
+	}
+
+	if output.IsJSON() {
+		emitDryRun(files, commitMessage)
+		return
+	}
+
+	fmt.Println("Dry run: nothing will be committed or pushed.")
+	fmt.Println("")
+
+	if len(files) == 0 {
+		fmt.Println("No changes would be committed.")
+	} else {
+		fmt.Printf("Changes that would be committed (%d files, %s):\n\n", len(files), formatFileSize(totalSize))
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, file := range files {
+			size := ""
+			if file.Status != "D" {
+				size = formatFileSize(file.Size)
+			}
+
+			fmt.Fprintf(writer, "  %s\t%s\t%s\n", file.Status, file.Path, size)
+		}
+		writer.Flush()
+	}
+
+	fmt.Println("")
+	fmt.Printf("Commit message: %q\n", commitMessage)
+}
+
+func emitDryRun(files []fileToCommit, commitMessage string) {
+	filesJson := []map[string]interface{}{}
+	for _, file := range files {
+		filesJson = append(filesJson, map[string]interface{}{"path": file.Path, "status": file.Status, "size": file.Size})
+	}
+
+	output.Emit("dry_run", map[string]interface{}{"files": filesJson, "commit_message": commitMessage})
+}
+
+func formatFileSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
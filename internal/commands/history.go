@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/history"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+// HistoryCommand lists past submissions for this repository, most recent first. Empty filters match all submissions,
+// and a limit of 0 lists all of them.
+func HistoryCommand(commandFilter string, statusFilter string, limit int, asJson bool) (err error) {
+	utils.Logger.Debug().Msg("history command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("history command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	records, err := loadHistoryRecords()
+	if err != nil {
+		return err
+	}
+
+	filteredRecords := []history.Record{}
+	for _, record := range records {
+		if commandFilter != "" && record.Command != commandFilter {
+			continue
+		}
+
+		if statusFilter != "" && record.Status != statusFilter {
+			continue
+		}
+
+		filteredRecords = append(filteredRecords, record)
+	}
+
+	if limit > 0 && len(filteredRecords) > limit {
+		filteredRecords = filteredRecords[:limit]
+	}
+
+	if asJson {
+		recordsJson, err := json.MarshalIndent(filteredRecords, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode history: %w", err)
+		}
+
+		fmt.Println(string(recordsJson))
+		return nil
+	}
+
+	if len(filteredRecords) == 0 {
+		fmt.Println("No submissions found. Submissions are recorded when you run `codecrafters test` or `codecrafters submit`.")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "CREATED\tSUBMISSION\tCOMMIT\tCOMMAND\tSTAGES\tSTATUS\tDURATION")
+
+	for _, record := range filteredRecords {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			record.SubmissionId,
+			shortCommitSha(record.CommitSha),
+			record.Command,
+			formatStageSelection(record),
+			record.Status,
+			formatRecordDuration(record),
+		)
+	}
+
+	writer.Flush()
+
+	return nil
+}
+
+// HistoryShowCommand shows a single past submission. submissionId can be a unique prefix of the submission's id.
+func HistoryShowCommand(submissionId string, asJson bool) (err error) {
+	utils.Logger.Debug().Msg("history show command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("history show command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	records, err := loadHistoryRecords()
+	if err != nil {
+		return err
+	}
+
+	record, err := findHistoryRecord(records, submissionId)
+	if err != nil {
+		return err
+	}
+
+	if asJson {
+		recordJson, err := json.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode history record: %w", err)
+		}
+
+		fmt.Println(string(recordJson))
+		return nil
+	}
+
+	finishedAt := "-"
+	if record.FinishedAt != nil {
+		finishedAt = record.FinishedAt.Local().Format(time.RFC1123)
+	}
+
+	fmt.Printf("Submission:  %s\n", record.SubmissionId)
+	fmt.Printf("Commit:      %s\n", record.CommitSha)
+	fmt.Printf("Command:     %s\n", record.Command)
+	fmt.Printf("Stages:      %s\n", formatStageSelection(record))
+	fmt.Printf("Status:      %s\n", record.Status)
+	fmt.Printf("Created at:  %s\n", record.CreatedAt.Local().Format(time.RFC1123))
+	fmt.Printf("Finished at: %s\n", finishedAt)
+	fmt.Printf("Duration:    %s\n", formatRecordDuration(record))
+
+	return nil
+}
+
+func loadHistoryRecords() ([]history.Record, error) {
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	store, err := history.OpenStore(codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Records()
+}
+
+func findHistoryRecord(records []history.Record, submissionIdPrefix string) (history.Record, error) {
+	matchingRecords := []history.Record{}
+
+	for _, record := range records {
+		if record.SubmissionId == submissionIdPrefix {
+			return record, nil
+		}
+
+		if strings.HasPrefix(record.SubmissionId, submissionIdPrefix) {
+			matchingRecords = append(matchingRecords, record)
+		}
+	}
+
+	if len(matchingRecords) == 0 {
+		return history.Record{}, fmt.Errorf("No submission found with id '%s'. Run `codecrafters history` to list submissions.", submissionIdPrefix)
+	}
+
+	if len(matchingRecords) > 1 {
+		return history.Record{}, fmt.Errorf("Submission id '%s' is ambiguous, it matches %d submissions.", submissionIdPrefix, len(matchingRecords))
+	}
+
+	return matchingRecords[0], nil
+}
+
+func formatStageSelection(record history.Record) string {
+	if len(record.StageSlugs) > 0 {
+		return strings.Join(record.StageSlugs, ",")
+	}
+
+	return record.StageSelectionStrategy
+}
+
+func formatRecordDuration(record history.Record) string {
+	duration, ok := record.Duration()
+	if !ok {
+		return "-"
+	}
+
+	return duration.Round(100 * time.Millisecond).String()
+}
+
+func shortCommitSha(commitSha string) string {
+	if len(commitSha) > 7 {
+		return commitSha[:7]
+	}
+
+	return commitSha
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -11,7 +12,7 @@ import (
 	"github.com/getsentry/sentry-go"
 )
 
-func PingCommand() (err error) {
+func PingCommand(ctx context.Context) (err error) {
 	utils.Logger.Debug().Msg("ping command starts")
 
 	defer func() {
@@ -36,6 +37,17 @@ func PingCommand() (err error) {
 			return
 		}
 
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		var exitErr actions.ExitError
+		if errors.As(err, &exitErr) {
+			// The server asked to exit, e.g. because tests failed
+			return
+		}
+
 		sentry.CurrentHub().CaptureException(err)
 	}()
 
@@ -50,19 +62,19 @@ func PingCommand() (err error) {
 
 	utils.Logger.Debug().Msg("identifying remotes")
 
-	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	codecraftersRemote, err := identifyGitRemote(repoDir)
 	if err != nil {
 		return err
 	}
 
 	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
 
-	globals.SetCodecraftersServerURL(codecraftersRemote.CodecraftersServerURL())
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
 	codecraftersClient := client.NewCodecraftersClient()
 
 	utils.Logger.Debug().Msg("sending ping request")
 
-	pingResponse, err := codecraftersClient.Ping(codecraftersRemote.CodecraftersRepositoryId())
+	pingResponse, err := codecraftersClient.Ping(ctx, codecraftersRemote.CodecraftersRepositoryId())
 	if err != nil {
 		return fmt.Errorf("ping: %w", err)
 	}
@@ -75,7 +87,7 @@ func PingCommand() (err error) {
 			return fmt.Errorf("parse action: %w", err)
 		}
 
-		if err := action.Execute(); err != nil {
+		if err := action.Execute(ctx); err != nil {
 			return fmt.Errorf("execute action: %w", err)
 		}
 	}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/utils"
+)
+
+// fetchStageList fetches the stage list and caches it locally, so that commands that can run offline (like local test
+// runs) can fall back to it.
+func fetchStageList(ctx context.Context, codecraftersClient client.CodecraftersClient, repositoryId string) (client.FetchStageListResponse, error) {
+	stageListResponse, err := codecraftersClient.FetchStageList(ctx, repositoryId)
+	if err != nil {
+		return client.FetchStageListResponse{}, err
+	}
+
+	if err := saveCachedStageList(repositoryId, stageListResponse); err != nil {
+		// The cache is best-effort
+		utils.Logger.Debug().Err(err).Msg("failed to cache stage list")
+	}
+
+	return stageListResponse, nil
+}
+
+func loadCachedStageList(repositoryId string) (client.FetchStageListResponse, error) {
+	cachePath, err := stageListCachePath(repositoryId)
+	if err != nil {
+		return client.FetchStageListResponse{}, err
+	}
+
+	content, err := os.ReadFile(cachePath)
+	if err != nil {
+		return client.FetchStageListResponse{}, fmt.Errorf("read cached stage list: %w", err)
+	}
+
+	stageListResponse := client.FetchStageListResponse{}
+	if err := json.Unmarshal(content, &stageListResponse); err != nil {
+		return client.FetchStageListResponse{}, fmt.Errorf("parse cached stage list: %w", err)
+	}
+
+	return stageListResponse, nil
+}
+
+func saveCachedStageList(repositoryId string, stageListResponse client.FetchStageListResponse) error {
+	cachePath, err := stageListCachePath(repositoryId)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	content, err := json.Marshal(stageListResponse)
+	if err != nil {
+		return fmt.Errorf("encode stage list: %w", err)
+	}
+
+	return os.WriteFile(cachePath, content, 0644)
+}
+
+func stageListCachePath(repositoryId string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("find cache dir: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "codecrafters", "stage_lists", repositoryId+".json"), nil
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+type stageListItem struct {
+	Number         int    `json:"number"` // 1-based, like the stage numbers in `codecrafters ui` and in instructions
+	Slug           string `json:"slug"`
+	Name           string `json:"name"`
+	RelativeOffset *int   `json:"relative_offset"`
+	Status         string `json:"status"` // "completed", "current" or "locked"
+}
+
+func StagesCommand(ctx context.Context, format string) (err error) {
+	utils.Logger.Debug().Msg("stages command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("stages command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	if format != "table" && format != "plain" && format != "json" {
+		return fmt.Errorf("Invalid format '%s'. Expected table, plain or json.", format)
+	}
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		return fmt.Errorf("fetch stage list: %w", err)
+	}
+
+	utils.Logger.Debug().Msgf("fetched %d stages", len(stageListResponse.Stages))
+
+	items := buildStageListItems(stageListResponse.Stages, stageListResponse.CurrentStageIndex())
+
+	switch format {
+	case "json":
+		itemsJson, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode stages: %w", err)
+		}
+
+		fmt.Println(string(itemsJson))
+	case "plain":
+		for _, item := range items {
+			fmt.Printf("%d\t%s\t%s\t%s\t%s\n", item.Number, item.Slug, item.Status, formatStageListItemOffset(item), item.Name)
+		}
+	case "table":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "  #\tSLUG\tNAME\tOFFSET\tSTATUS")
+
+		for _, item := range items {
+			marker := "  "
+			if item.Status == "current" {
+				marker = "→ "
+			}
+
+			fmt.Fprintf(writer, "%s%d\t%s\t%s\t%s\t%s\n", marker, item.Number, item.Slug, item.Name, formatStageListItemOffset(item), item.Status)
+		}
+
+		writer.Flush()
+	}
+
+	return nil
+}
+
+// buildStageListItems marks stages before the current stage as completed, and stages after it as locked. If there's no
+// current stage (i.e. the challenge is complete), all stages are marked as completed.
+func buildStageListItems(stages []client.Stage, currentStageIndex int) []stageListItem {
+	items := []stageListItem{}
+
+	for i, stage := range stages {
+		item := stageListItem{
+			Number: i + 1,
+			Slug:   stage.Slug,
+			Name:   stage.Name,
+			Status: "completed",
+		}
+
+		if currentStageIndex != -1 {
+			relativeOffset := i - currentStageIndex
+			item.RelativeOffset = &relativeOffset
+
+			if i == currentStageIndex {
+				item.Status = "current"
+			} else if i > currentStageIndex {
+				item.Status = "locked"
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func formatStageListItemOffset(item stageListItem) string {
+	if item.RelativeOffset == nil {
+		return "-"
+	}
+
+	return formatStageOffset(*item.RelativeOffset)
+}
+
+// formatStageOffset formats an offset relative to the current stage the way `--stage` accepts it (+1, -2, 0)
+func formatStageOffset(relativeOffset int) string {
+	if relativeOffset > 0 {
+		return fmt.Sprintf("+%d", relativeOffset)
+	}
+
+	return fmt.Sprintf("%d", relativeOffset)
+}
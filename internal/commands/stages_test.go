@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildStageListItems(t *testing.T) {
+	stages := []client.Stage{{Slug: "oo8"}, {Slug: "cz2"}, {Slug: "ff0"}}
+
+	items := buildStageListItems(stages, 1)
+	assert.Equal(t, []int{1, 2, 3}, []int{items[0].Number, items[1].Number, items[2].Number})
+	assert.Equal(t, []string{"completed", "current", "locked"}, []string{items[0].Status, items[1].Status, items[2].Status})
+	assert.Equal(t, "-1", formatStageListItemOffset(items[0]))
+	assert.Equal(t, "+1", formatStageListItemOffset(items[2]))
+
+	// Completed challenges have no current stage to be relative to
+	items = buildStageListItems(stages, -1)
+	assert.Equal(t, "completed", items[2].Status)
+	assert.Equal(t, "-", formatStageListItemOffset(items[2]))
+}
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+type repositoryStatus struct {
+	RemoteName                 string `json:"remote_name"`
+	RemoteUrl                  string `json:"remote_url"`
+	RepositoryId               string `json:"repository_id"`
+	ServerUrl                  string `json:"server_url"`
+	CurrentStageSlug           string `json:"current_stage_slug"`
+	CurrentStageName           string `json:"current_stage_name"`
+	CurrentBuildpackSlug       string `json:"current_buildpack_slug"`
+	LatestBuildpackSlug        string `json:"latest_buildpack_slug"`
+	IsBuildpackUpdateAvailable bool   `json:"is_buildpack_update_available"`
+	HasUncommittedChanges      bool   `json:"has_uncommitted_changes"`
+}
+
+func StatusCommand(ctx context.Context, asJson bool) (err error) {
+	utils.Logger.Debug().Msg("status command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("status command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	status := repositoryStatus{
+		RemoteName:   codecraftersRemote.Name,
+		RemoteUrl:    codecraftersRemote.Url,
+		RepositoryId: codecraftersRemote.CodecraftersRepositoryId(),
+		ServerUrl:    codecraftersServerURL(codecraftersRemote),
+	}
+
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, status.RepositoryId)
+	if err != nil {
+		return fmt.Errorf("fetch stage list: %w", err)
+	}
+
+	if currentStageIndex := stageListResponse.CurrentStageIndex(); currentStageIndex != -1 {
+		status.CurrentStageSlug = stageListResponse.Stages[currentStageIndex].Slug
+		status.CurrentStageName = stageListResponse.Stages[currentStageIndex].Name
+	}
+
+	utils.Logger.Debug().Msg("fetching current buildpack")
+
+	repositoryBuildpackResponse, err := codecraftersClient.FetchRepositoryBuildpack(ctx, status.RepositoryId)
+	if err != nil {
+		return fmt.Errorf("fetch repository buildpack: %w", err)
+	}
+
+	status.CurrentBuildpackSlug = repositoryBuildpackResponse.Buildpack.Slug
+
+	utils.Logger.Debug().Msg("fetching buildpacks")
+
+	buildpacksResponse, err := codecraftersClient.FetchBuildpacks(ctx, status.RepositoryId)
+	if err != nil {
+		return fmt.Errorf("fetch buildpacks: %w", err)
+	}
+
+	for _, buildpack := range buildpacksResponse.Buildpacks {
+		if buildpack.IsLatest {
+			status.LatestBuildpackSlug = buildpack.Slug
+			break
+		}
+	}
+
+	status.IsBuildpackUpdateAvailable = status.LatestBuildpackSlug != "" && status.LatestBuildpackSlug != status.CurrentBuildpackSlug
+
+	utils.Logger.Debug().Msg("checking for uncommitted changes")
+
+	status.HasUncommittedChanges, err = hasUncommittedChanges(repoDir)
+	if err != nil {
+		return fmt.Errorf("check for uncommitted changes: %w", err)
+	}
+
+	if asJson {
+		statusJson, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode status: %w", err)
+		}
+
+		fmt.Println(string(statusJson))
+		return nil
+	}
+
+	currentStage := "none"
+	if status.CurrentStageSlug != "" {
+		currentStage = fmt.Sprintf("%s [%s]", status.CurrentStageName, status.CurrentStageSlug)
+	}
+
+	buildpack := status.CurrentBuildpackSlug
+	if status.IsBuildpackUpdateAvailable {
+		buildpack += fmt.Sprintf(" (%s available, run `codecrafters update-buildpack` to upgrade)", status.LatestBuildpackSlug)
+	} else if status.LatestBuildpackSlug != "" && status.CurrentBuildpackSlug == status.LatestBuildpackSlug {
+		buildpack += " (latest)"
+	}
+
+	uncommittedChanges := "no"
+	if status.HasUncommittedChanges {
+		uncommittedChanges = "yes"
+	}
+
+	fmt.Printf("Remote:              %s (%s)\n", status.RemoteName, status.RemoteUrl)
+	fmt.Printf("Repository ID:       %s\n", status.RepositoryId)
+	fmt.Printf("Server:              %s\n", status.ServerUrl)
+	fmt.Printf("Current stage:       %s\n", currentStage)
+	fmt.Printf("Buildpack:           %s\n", buildpack)
+	fmt.Printf("Uncommitted changes: %s\n", uncommittedChanges)
+
+	return nil
+}
+
+func hasUncommittedChanges(repoDir string) (bool, error) {
+	outputBytes, err := exec.Command("git", "-C", repoDir, "status", "--porcelain").CombinedOutput()
+	if err != nil {
+		return false, wrapError(err, outputBytes, "run git status")
+	}
+
+	return strings.TrimSpace(string(outputBytes)) != "", nil
+}
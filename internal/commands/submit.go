@@ -1,18 +1,25 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/codecrafters-io/cli/internal/actions"
 	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/config"
 	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/history"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/getsentry/sentry-go"
 )
 
-func SubmitCommand(commitMessage string) (err error) {
+// SubmitCommand commits all changes and runs tests. If isDryRun is set, the changes that would be committed are printed instead.
+func SubmitCommand(ctx context.Context, commitMessage string, isDryRun bool) (err error) {
 	utils.Logger.Debug().Msg("submit command starts")
 
 	defer func() {
@@ -37,6 +44,17 @@ func SubmitCommand(commitMessage string) (err error) {
 			return
 		}
 
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		var exitErr actions.ExitError
+		if errors.As(err, &exitErr) {
+			// The server asked to exit, e.g. because tests failed
+			return
+		}
+
 		sentry.CurrentHub().CaptureException(err)
 	}()
 
@@ -51,7 +69,7 @@ func SubmitCommand(commitMessage string) (err error) {
 
 	utils.Logger.Debug().Msg("identifying remotes")
 
-	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	codecraftersRemote, err := identifyGitRemote(repoDir)
 	if err != nil {
 		return err
 	}
@@ -63,12 +81,22 @@ func SubmitCommand(commitMessage string) (err error) {
 		return fmt.Errorf("get current branch: %w", err)
 	}
 
-	defaultBranchName := "master" // TODO: Change when we allow customizing the defaultBranch
+	defaultBranchName := config.Get("submit.branch")
 
 	if currentBranchName != defaultBranchName {
 		return fmt.Errorf("You need to be on the `%s` branch to run this command.", defaultBranchName)
 	}
 
+	if isDryRun {
+		filesToCommit, err := listFilesToCommit(repoDir)
+		if err != nil {
+			return fmt.Errorf("list files to commit: %w", err)
+		}
+
+		printDryRun(filesToCommit, commitMessage)
+		return nil
+	}
+
 	utils.Logger.Debug().Msgf("committing changes to %s", defaultBranchName)
 	commitSha, err := commitChanges(repoDir, commitMessage)
 	if err != nil {
@@ -76,28 +104,40 @@ func SubmitCommand(commitMessage string) (err error) {
 	}
 
 	// Place this before the push so that it "feels" fast.
-	fmt.Printf("Submitting changes (commit: %s)...\n\n", commitSha[:7])
+	output.Printf("Submitting changes (commit: %s)...\n\n", commitSha[:7])
 
-	err = pushBranchToRemote(repoDir, codecraftersRemote.Name)
+	err = pushBranchToRemote(ctx, repoDir, codecraftersRemote.Name)
 	if err != nil {
 		return fmt.Errorf("push changes: %w", err)
 	}
 
 	utils.Logger.Debug().Msgf("pushed changes to remote branch %s", defaultBranchName)
 
-	globals.SetCodecraftersServerURL(codecraftersRemote.CodecraftersServerURL())
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
 	codecraftersClient := client.NewCodecraftersClient()
 
 	utils.Logger.Debug().Msgf("creating submission for %s", commitSha)
 
-	createSubmissionResponse, err := codecraftersClient.CreateSubmission(codecraftersRemote.CodecraftersRepositoryId(), commitSha, "submit", "current_and_previous_descending")
+	createSubmissionResponse, err := codecraftersClient.CreateSubmission(ctx, codecraftersRemote.CodecraftersRepositoryId(), commitSha, "submit", "current_and_previous_descending", nil)
 	if err != nil {
 		return fmt.Errorf("create submission: %w", err)
 	}
 
 	utils.Logger.Debug().Msgf("submission created: %v", createSubmissionResponse.Id)
 
-	return handleSubmission(createSubmissionResponse, codecraftersClient)
+	output.Emit("submission_created", map[string]interface{}{"submission_id": createSubmissionResponse.Id, "commit_sha": commitSha, "command": "submit"})
+
+	recordSubmission(codecraftersRemote.CodecraftersRepositoryId(), history.Record{
+		SubmissionId:           createSubmissionResponse.Id,
+		CommitSha:              commitSha,
+		Command:                "submit",
+		StageSelectionStrategy: "current_and_previous_descending",
+		StageSlugs:             nil,
+		Status:                 "evaluating",
+		CreatedAt:              time.Now(),
+	})
+
+	return handleSubmission(ctx, createSubmissionResponse, codecraftersClient)
 }
 
 func getCurrentBranch(repoDir string) (string, error) {
@@ -1,18 +1,21 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/codecrafters-io/cli/internal/client"
 	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/getsentry/sentry-go"
 )
 
-func TaskCommand(stageSlug string, raw bool) (err error) {
+func TaskCommand(ctx context.Context, stageSlug string, raw bool) (err error) {
 	utils.Logger.Debug().Msg("task command starts")
 
 	defer func() {
@@ -37,6 +40,11 @@ func TaskCommand(stageSlug string, raw bool) (err error) {
 			return
 		}
 
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
 		sentry.CurrentHub().CaptureException(err)
 	}()
 
@@ -51,63 +59,44 @@ func TaskCommand(stageSlug string, raw bool) (err error) {
 
 	utils.Logger.Debug().Msg("identifying remotes")
 
-	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	codecraftersRemote, err := identifyGitRemote(repoDir)
 	if err != nil {
 		return err
 	}
 
 	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
 
-	globals.SetCodecraftersServerURL(codecraftersRemote.CodecraftersServerURL())
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
 	codecraftersClient := client.NewCodecraftersClient()
 
 	utils.Logger.Debug().Msg("fetching stage list")
 
-	stageListResponse, err := codecraftersClient.FetchStageList(codecraftersRemote.CodecraftersRepositoryId())
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId())
 	if err != nil {
 		return fmt.Errorf("fetch stage list: %w", err)
 	}
 
 	utils.Logger.Debug().Msgf("fetched %d stages", len(stageListResponse.Stages))
 
-	var currentStageIndex int = -1
-	for i := range stageListResponse.Stages {
-		if stageListResponse.Stages[i].IsCurrent {
-			currentStageIndex = i
-			break
-		}
-	}
-
+	currentStageIndex := stageListResponse.CurrentStageIndex()
 	if currentStageIndex == -1 {
 		panic("no current stage found")
 	}
 
-	var targetStage *client.Stage
-
-	if stageSlug == "" {
-		targetStage = &stageListResponse.Stages[currentStageIndex]
-	} else if offset, err := strconv.Atoi(stageSlug); err == nil {
-		targetIndex := currentStageIndex + offset
-
-		if targetIndex < 0 || targetIndex >= len(stageListResponse.Stages) {
-			return buildStageError(fmt.Sprintf("Stage offset %d is out of range", offset), currentStageIndex, stageListResponse.Stages)
-		}
-
-		targetStage = &stageListResponse.Stages[targetIndex]
-	} else {
-		for i := range stageListResponse.Stages {
-			if stageListResponse.Stages[i].Slug == stageSlug {
-				targetStage = &stageListResponse.Stages[i]
-				break
-			}
-		}
-
-		if targetStage == nil {
-			return buildStageError(fmt.Sprintf("Stage '%s' not found", stageSlug), currentStageIndex, stageListResponse.Stages)
-		}
+	targetStageIndex, err := resolveStageIndex(stageListResponse.Stages, currentStageIndex, stageSlug)
+	if err != nil {
+		return err
 	}
 
-	if raw {
+	targetStage := &stageListResponse.Stages[targetStageIndex]
+
+	if output.IsJSON() {
+		output.Emit("stage_instructions", map[string]interface{}{
+			"stage_slug": targetStage.Slug,
+			"stage_name": targetStage.Name,
+			"markdown":   targetStage.GetDocsMarkdown(),
+		})
+	} else if raw {
 		fmt.Println(targetStage.GetDocsMarkdown())
 	} else {
 		renderer, err := glamour.NewTermRenderer(
@@ -128,6 +117,56 @@ func TaskCommand(stageSlug string, raw bool) (err error) {
 	return nil
 }
 
+// resolveStageIndex resolves a stage argument (empty for the current stage, a slug, or an offset like +1 or -2) to an index in stages
+func resolveStageIndex(stages []client.Stage, currentStageIndex int, stageArg string) (int, error) {
+	if stageArg == "" {
+		return currentStageIndex, nil
+	}
+
+	if offset, err := strconv.Atoi(stageArg); err == nil {
+		targetIndex := currentStageIndex + offset
+
+		if targetIndex < 0 || targetIndex >= len(stages) {
+			return -1, buildStageError(fmt.Sprintf("Stage offset %d is out of range", offset), currentStageIndex, stages)
+		}
+
+		return targetIndex, nil
+	}
+
+	for i := range stages {
+		if stages[i].Slug == stageArg {
+			return i, nil
+		}
+	}
+
+	return -1, buildStageError(fmt.Sprintf("Stage '%s' not found", stageArg), currentStageIndex, stages)
+}
+
+// resolveStageRange resolves a range argument like "jm1..ff3" or "-3..-1" to the (inclusive) start and end indices of the
+// range in stages. Both ends of the range are resolved the same way as resolveStageIndex.
+func resolveStageRange(stages []client.Stage, currentStageIndex int, rangeArg string) (int, int, error) {
+	startArg, endArg, found := strings.Cut(rangeArg, "..")
+	if !found || startArg == "" || endArg == "" {
+		return -1, -1, fmt.Errorf("Invalid stage range '%s'. Expected a range like <stage>..<stage>, e.g. 3..7 or -3..-1.", rangeArg)
+	}
+
+	startIndex, err := resolveStageIndex(stages, currentStageIndex, startArg)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	endIndex, err := resolveStageIndex(stages, currentStageIndex, endArg)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if startIndex > endIndex {
+		return -1, -1, buildStageError(fmt.Sprintf("Stage range '%s' is empty, '%s' comes after '%s'", rangeArg, startArg, endArg), currentStageIndex, stages)
+	}
+
+	return startIndex, endIndex, nil
+}
+
 func buildStageError(message string, currentStageIndex int, stages []client.Stage) error {
 	errorMsg := fmt.Sprintf("%s.\n\n", message)
 	errorMsg += "Available stages:\n\n"
@@ -139,12 +178,10 @@ func buildStageError(message string, currentStageIndex int, stages []client.Stag
 		}
 		relativeOffset := i - currentStageIndex
 		offsetStr := ""
-		if relativeOffset > 0 {
-			offsetStr = fmt.Sprintf(" (+%d)", relativeOffset)
-		} else if relativeOffset < 0 {
-			offsetStr = fmt.Sprintf(" (%d)", relativeOffset)
+		if relativeOffset != 0 {
+			offsetStr = fmt.Sprintf(" (%s)", formatStageOffset(relativeOffset))
 		}
-		errorMsg += fmt.Sprintf("%s%d. [%s] %s%s\n", marker, i, stage.Slug, stage.Name, offsetStr)
+		errorMsg += fmt.Sprintf("%s%d. [%s] %s%s\n", marker, i+1, stage.Slug, stage.Name, offsetStr)
 	}
 
 	return fmt.Errorf("%s", errorMsg)
@@ -0,0 +1,397 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/output"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+	"github.com/levigross/grequests"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// exportedPage is a page written by TaskExportCommand: the index or a stage
+type exportedPage struct {
+	Title    string
+	FileName string
+	Markdown string
+
+	// Empty if there's no such page
+	PreviousFileName string
+	NextFileName     string
+}
+
+// exportAssetsDirName is the directory in the export directory that images and other embedded files are downloaded to
+const exportAssetsDirName = "assets"
+
+// exportAssetUrlRegex matches the URLs of remote files embedded in instructions: Markdown images, and the src
+// attributes of raw HTML (e.g. <img> or <video>). Links to other pages are left alone.
+var exportAssetUrlRegex = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?(https?://[^)\s>]+)|\bsrc=["'](https?://[^"']+)["']`)
+
+// TaskExportCommand writes the instructions of every stage to its own file in exportDir, along with an index page that
+// links to them in order. format is either md (Markdown) or html (standalone pages that work offline). Images are
+// downloaded next to the pages in both formats.
+func TaskExportCommand(ctx context.Context, exportDir string, format string) (err error) {
+	utils.Logger.Debug().Msg("task export command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("task export command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	if format != "md" && format != "html" {
+		return fmt.Errorf("Invalid format '%s'. Expected md or html.", format)
+	}
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil {
+		return fmt.Errorf("fetch stage list: %w", err)
+	}
+
+	utils.Logger.Debug().Msgf("fetched %d stages", len(stageListResponse.Stages))
+
+	if err := os.MkdirAll(exportDir, 0755); err != nil {
+		return fmt.Errorf("create export directory: %w", err)
+	}
+
+	pages := buildExportedPages(stageListResponse, format)
+
+	pages, err = downloadExportedAssets(ctx, pages, exportDir)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		var content []byte
+
+		if format == "html" {
+			content, err = renderExportedPageHTML(page)
+			if err != nil {
+				return err
+			}
+		} else {
+			content = []byte(renderExportedPageMarkdown(page))
+		}
+
+		if err := os.WriteFile(filepath.Join(exportDir, page.FileName), content, 0644); err != nil {
+			return fmt.Errorf("write %s: %w", page.FileName, err)
+		}
+	}
+
+	output.Printf("Exported %d stages to %s\n", len(stageListResponse.Stages), filepath.Join(exportDir, pages[0].FileName))
+
+	return nil
+}
+
+// buildExportedPages returns the index page followed by one page per stage
+func buildExportedPages(stageListResponse client.FetchStageListResponse, fileExtension string) []exportedPage {
+	stages := stageListResponse.Stages
+	fileNames := make([]string, len(stages))
+
+	// Zero-padded so that files sort in stage order
+	indexWidth := len(fmt.Sprint(len(stages)))
+	for i, stage := range stages {
+		// Slugs come from the server, but shouldn't be able to write outside of the export directory regardless
+		fileNames[i] = fmt.Sprintf("%0*d-%s.%s", indexWidth, i+1, sanitizeFileName(stage.Slug), fileExtension)
+	}
+
+	var indexMarkdown bytes.Buffer
+	indexMarkdown.WriteString("# Stage instructions\n\n")
+
+	for i, stage := range stages {
+		fmt.Fprintf(&indexMarkdown, "%d. [%s](%s)", i+1, stage.Name, fileNames[i])
+
+		if stage.IsCurrent {
+			indexMarkdown.WriteString(" (current stage)")
+		}
+
+		indexMarkdown.WriteString("\n")
+	}
+
+	pages := []exportedPage{{
+		Title:    "Stage instructions",
+		FileName: "index." + fileExtension,
+		Markdown: indexMarkdown.String(),
+	}}
+
+	for i, stage := range stages {
+		page := exportedPage{
+			Title:    fmt.Sprintf("Stage #%d: %s", i+1, stage.Name),
+			FileName: fileNames[i],
+			Markdown: stage.GetDocsMarkdown(),
+		}
+
+		if i > 0 {
+			page.PreviousFileName = fileNames[i-1]
+		}
+
+		if i < len(stages)-1 {
+			page.NextFileName = fileNames[i+1]
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages
+}
+
+// downloadExportedAssets downloads the remote files embedded in pages to the assets directory, and returns the pages
+// with their URLs replaced by the local paths. Files that can't be downloaded are left as remote URLs, with a warning.
+func downloadExportedAssets(ctx context.Context, pages []exportedPage, exportDir string) ([]exportedPage, error) {
+	localPaths := map[string]string{}
+	updatedPages := []exportedPage{}
+
+	for _, page := range pages {
+		for _, assetUrl := range findExportedAssetUrls(page.Markdown) {
+			localPath, ok := localPaths[assetUrl]
+
+			if !ok {
+				var err error
+
+				localPath, err = downloadExportedAsset(ctx, assetUrl, exportDir)
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+
+				if err != nil {
+					utils.Logger.Debug().Err(err).Msgf("failed to download %s", assetUrl)
+					output.Printf("Warning: couldn't download %s, %s links to it instead.\n", assetUrl, page.FileName)
+				}
+
+				localPaths[assetUrl] = localPath
+			}
+
+			if localPath != "" {
+				page.Markdown = strings.ReplaceAll(page.Markdown, assetUrl, localPath)
+			}
+		}
+
+		updatedPages = append(updatedPages, page)
+	}
+
+	return updatedPages, nil
+}
+
+// findExportedAssetUrls returns the unique URLs of the remote files embedded in markdown, in order
+func findExportedAssetUrls(markdown string) []string {
+	assetUrls := []string{}
+
+	for _, match := range exportAssetUrlRegex.FindAllStringSubmatch(markdown, -1) {
+		assetUrl := match[1]
+		if assetUrl == "" {
+			assetUrl = match[2]
+		}
+
+		if !slices.Contains(assetUrls, assetUrl) {
+			assetUrls = append(assetUrls, assetUrl)
+		}
+	}
+
+	return assetUrls
+}
+
+// downloadExportedAsset downloads assetUrl to the assets directory, and returns its path relative to exportDir
+func downloadExportedAsset(ctx context.Context, assetUrl string, exportDir string) (string, error) {
+	response, err := grequests.Get(assetUrl, &grequests.RequestOptions{HTTPClient: client.HTTPClient(), Context: ctx})
+	if err != nil {
+		return "", err
+	}
+
+	if !response.Ok {
+		return "", fmt.Errorf("status code: %d", response.StatusCode)
+	}
+
+	localPath := path.Join(exportAssetsDirName, exportedAssetFileName(assetUrl))
+
+	if err := os.MkdirAll(filepath.Join(exportDir, exportAssetsDirName), 0755); err != nil {
+		return "", fmt.Errorf("create assets directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(exportDir, filepath.FromSlash(localPath)), response.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("write %s: %w", localPath, err)
+	}
+
+	return localPath, nil
+}
+
+// exportedAssetFileName returns a file name for assetUrl that's unique to it, and keeps its extension so that browsers
+// know what it is
+func exportedAssetFileName(assetUrl string) string {
+	hash := sha256.Sum256([]byte(assetUrl))
+	prefix := hex.EncodeToString(hash[:4])
+
+	parsedUrl, err := url.Parse(assetUrl)
+	if err != nil || path.Base(parsedUrl.Path) == "/" || path.Base(parsedUrl.Path) == "." {
+		return prefix
+	}
+
+	return prefix + "-" + sanitizeFileName(path.Base(parsedUrl.Path))
+}
+
+// sanitizeFileName replaces the characters of name that aren't safe in file names (including path separators and
+// dots, except for the extension's) with dashes
+func sanitizeFileName(name string) string {
+	extension := path.Ext(name)
+	if strings.ContainsFunc(extension, func(r rune) bool { return !isFileNameRune(r) && r != '.' }) {
+		extension = ""
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		if isFileNameRune(r) {
+			return r
+		}
+
+		return '-'
+	}, strings.TrimSuffix(name, extension))
+
+	if sanitized == "" {
+		sanitized = "-"
+	}
+
+	return sanitized + extension
+}
+
+func isFileNameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+func renderExportedPageMarkdown(page exportedPage) string {
+	// The index has no navigation
+	if page.FileName == "index.md" {
+		return page.Markdown
+	}
+
+	navigation := "[Index](index.md)"
+
+	if page.PreviousFileName != "" {
+		navigation = fmt.Sprintf("[← Previous](%s) | %s", page.PreviousFileName, navigation)
+	}
+
+	if page.NextFileName != "" {
+		navigation = fmt.Sprintf("%s | [Next →](%s)", navigation, page.NextFileName)
+	}
+
+	return fmt.Sprintf("%s\n\n---\n\n%s\n", page.Markdown, navigation)
+}
+
+var exportedPageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 48rem; margin: 2rem auto; padding: 0 1rem; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: #1f2328; }
+pre { background: #f6f8fa; padding: 1rem; overflow-x: auto; border-radius: 6px; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.9em; }
+:not(pre) > code { background: #f6f8fa; padding: 0.1em 0.3em; border-radius: 4px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.8rem; }
+img { max-width: 100%; }
+nav { display: flex; gap: 1rem; margin: 2rem 0; padding-top: 1rem; border-top: 1px solid #d0d7de; }
+</style>
+</head>
+<body>
+{{.Body}}
+{{- if not .IsIndex}}
+<nav>
+{{- if .PreviousFileName}}<a href="{{.PreviousFileName}}">← Previous</a>{{end}}
+<a href="index.html">Index</a>
+{{- if .NextFileName}}<a href="{{.NextFileName}}">Next →</a>{{end}}
+</nav>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderExportedPageHTML renders a page as a standalone HTML document, with styles inlined so that it works offline
+func renderExportedPageHTML(page exportedPage) ([]byte, error) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		// Instructions can contain raw HTML (e.g. <details>), and come from CodeCrafters
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	var body bytes.Buffer
+	if err := markdown.Convert([]byte(page.Markdown), &body); err != nil {
+		return nil, fmt.Errorf("render %s: %w", page.FileName, err)
+	}
+
+	var document bytes.Buffer
+	err := exportedPageTemplate.Execute(&document, map[string]interface{}{
+		"Title":            page.Title,
+		"Body":             template.HTML(body.String()),
+		"IsIndex":          page.FileName == "index.html",
+		"PreviousFileName": page.PreviousFileName,
+		"NextFileName":     page.NextFileName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("render %s: %w", page.FileName, err)
+	}
+
+	return document.Bytes(), nil
+}
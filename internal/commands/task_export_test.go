@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportedStageListResponse(stageCount int) client.FetchStageListResponse {
+	stages := []client.Stage{}
+	for i := 0; i < stageCount; i++ {
+		stages = append(stages, client.Stage{Slug: string(rune('a'+i)) + "x1", Name: "Stage " + string(rune('A'+i)), InstructionsMarkdown: "Do the thing."})
+	}
+
+	stages[1].IsCurrent = true
+
+	return client.FetchStageListResponse{Stages: stages}
+}
+
+func TestBuildExportedPages(t *testing.T) {
+	pages := buildExportedPages(exportedStageListResponse(12), "md")
+	require.Len(t, pages, 13)
+
+	t.Run("file names sort in stage order", func(t *testing.T) {
+		fileNames := []string{}
+		for _, page := range pages {
+			fileNames = append(fileNames, page.FileName)
+		}
+
+		assert.Equal(t, "index.md", fileNames[0])
+		assert.Equal(t, "01-ax1.md", fileNames[1])
+		assert.Equal(t, "12-lx1.md", fileNames[12])
+		assert.IsIncreasing(t, fileNames[1:])
+	})
+
+	t.Run("index links to every stage in order", func(t *testing.T) {
+		assert.Equal(t, "Stage instructions", pages[0].Title)
+		assert.Contains(t, pages[0].Markdown, "1. [Stage A](01-ax1.md)\n2. [Stage B](02-bx1.md) (current stage)\n3. [Stage C](03-cx1.md)\n")
+	})
+
+	t.Run("stages link to their neighbours", func(t *testing.T) {
+		assert.Equal(t, "Stage #1: Stage A", pages[1].Title)
+		assert.Empty(t, pages[1].PreviousFileName)
+		assert.Equal(t, "02-bx1.md", pages[1].NextFileName)
+
+		assert.Equal(t, "01-ax1.md", pages[2].PreviousFileName)
+		assert.Equal(t, "03-cx1.md", pages[2].NextFileName)
+
+		assert.Equal(t, "11-kx1.md", pages[12].PreviousFileName)
+		assert.Empty(t, pages[12].NextFileName)
+	})
+}
+
+func TestBuildExportedPagesSanitizesSlugs(t *testing.T) {
+	pages := buildExportedPages(client.FetchStageListResponse{Stages: []client.Stage{{Slug: "../../etc/passwd"}, {Slug: "a\\b"}}}, "html")
+
+	assert.Equal(t, "1-------etc-passwd.html", pages[1].FileName)
+	assert.Equal(t, "2-a-b.html", pages[2].FileName)
+
+	for _, page := range pages {
+		assert.Equal(t, filepath.Base(page.FileName), page.FileName)
+	}
+}
+
+func TestRenderExportedPageMarkdown(t *testing.T) {
+	pages := buildExportedPages(exportedStageListResponse(3), "md")
+
+	assert.Equal(t, pages[0].Markdown, renderExportedPageMarkdown(pages[0]))
+	assert.True(t, strings.HasSuffix(renderExportedPageMarkdown(pages[1]), "\n---\n\n[Index](index.md) | [Next →](2-bx1.md)\n"))
+	assert.True(t, strings.HasSuffix(renderExportedPageMarkdown(pages[2]), "\n---\n\n[← Previous](1-ax1.md) | [Index](index.md) | [Next →](3-cx1.md)\n"))
+	assert.True(t, strings.HasSuffix(renderExportedPageMarkdown(pages[3]), "\n---\n\n[← Previous](2-bx1.md) | [Index](index.md)\n"))
+}
+
+func TestRenderExportedPageHTML(t *testing.T) {
+	pages := buildExportedPages(exportedStageListResponse(3), "html")
+
+	index, err := renderExportedPageHTML(pages[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "<title>Stage instructions</title>")
+	assert.Contains(t, string(index), `<a href="2-bx1.html">Stage B</a> (current stage)`)
+	assert.NotContains(t, string(index), "<nav>")
+
+	stage, err := renderExportedPageHTML(pages[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(stage), "<title>Stage #2: Stage B</title>")
+	assert.Contains(t, string(stage), "<p>Do the thing.</p>")
+	assert.Contains(t, string(stage), "<nav><a href=\"1-ax1.html\">← Previous</a>\n<a href=\"index.html\">Index</a><a href=\"3-cx1.html\">Next →</a>\n</nav>")
+}
+
+func TestDownloadExportedAssets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		io.WriteString(w, "image "+r.URL.Path)
+	}))
+	defer server.Close()
+
+	exportDir := t.TempDir()
+	pages := []exportedPage{
+		{FileName: "1-ax1.md", Markdown: "![diagram](" + server.URL + "/diagram.png)\n\n[docs](" + server.URL + "/docs)"},
+		{FileName: "2-bx1.md", Markdown: `<img src="` + server.URL + `/diagram.png"> ![gone](` + server.URL + `/missing.png)`},
+	}
+
+	pages, err := downloadExportedAssets(context.Background(), pages, exportDir)
+	require.NoError(t, err)
+
+	localPath := "assets/" + exportedAssetFileName(server.URL+"/diagram.png")
+	assert.True(t, strings.HasSuffix(localPath, "-diagram.png"))
+
+	// Links to pages aren't assets, and missing assets keep their remote URL
+	assert.Equal(t, "![diagram]("+localPath+")\n\n[docs]("+server.URL+"/docs)", pages[0].Markdown)
+	assert.Equal(t, `<img src="`+localPath+`"> ![gone](`+server.URL+`/missing.png)`, pages[1].Markdown)
+
+	content, err := os.ReadFile(filepath.Join(exportDir, localPath))
+	require.NoError(t, err)
+	assert.Equal(t, "image /diagram.png", string(content))
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStageRange(t *testing.T) {
+	stages := []client.Stage{{Slug: "oo8"}, {Slug: "cz2"}, {Slug: "ff0"}, {Slug: "jm1"}, {Slug: "bq4"}}
+
+	startIndex, endIndex, err := resolveStageRange(stages, 2, "-2..0")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, []int{startIndex, endIndex})
+
+	startIndex, endIndex, err = resolveStageRange(stages, 2, "+1..+2")
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, []int{startIndex, endIndex})
+
+	startIndex, endIndex, err = resolveStageRange(stages, 2, "cz2..jm1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, []int{startIndex, endIndex})
+
+	// Unsigned numbers are offsets too, like in `task --stage`
+	startIndex, endIndex, err = resolveStageRange(stages, 1, "1..3")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, []int{startIndex, endIndex})
+
+	_, _, err = resolveStageRange(stages, 2, "+2..+1")
+	assert.ErrorContains(t, err, "is empty")
+
+	_, _, err = resolveStageRange(stages, 2, "+1")
+	assert.ErrorContains(t, err, "Invalid stage range")
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,14 +10,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/codecrafters-io/cli/internal/actions"
 	"github.com/codecrafters-io/cli/internal/client"
 	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/history"
+	"github.com/codecrafters-io/cli/internal/output"
 	"github.com/codecrafters-io/cli/internal/utils"
 	"github.com/getsentry/sentry-go"
 	cp "github.com/otiai10/copy"
 )
 
-func TestCommand(shouldTestPrevious bool) (err error) {
+// TestCommand runs tests against the current stage. If stageArg (a slug or offset) or stageRangeArg (e.g. "3..7") is
+// set, only those stages are tested. If isDryRun is set, the changes that would be pushed are printed instead.
+func TestCommand(ctx context.Context, shouldTestPrevious bool, stageArg string, stageRangeArg string, isDryRun bool) (err error) {
 	utils.Logger.Debug().Msg("test command starts")
 	defer func() {
 		utils.Logger.Debug().Err(err).Msg("test command ends")
@@ -40,6 +46,17 @@ func TestCommand(shouldTestPrevious bool) (err error) {
 			return
 		}
 
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		var exitErr actions.ExitError
+		if errors.As(err, &exitErr) {
+			// The server asked to exit, e.g. because tests failed
+			return
+		}
+
 		sentry.CurrentHub().CaptureException(err)
 	}()
 
@@ -54,16 +71,38 @@ func TestCommand(shouldTestPrevious bool) (err error) {
 
 	utils.Logger.Debug().Msg("identifying remotes")
 
-	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	codecraftersRemote, err := identifyGitRemote(repoDir)
 	if err != nil {
 		return err
 	}
 
 	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
 
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	stageSelectionStrategy := "current_and_previous_descending"
+	var stageSlugs []string
+
+	if shouldTestPrevious {
+		stageSelectionStrategy = "current_and_previous_ascending"
+	}
+
+	// Resolve stages before pushing, so that a typo doesn't cost a push
+	if stageArg != "" || stageRangeArg != "" {
+		stageSelectionStrategy = "specific_stages"
+
+		stageSlugs, err = resolveTestStageSlugs(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId(), stageArg, stageRangeArg)
+		if err != nil {
+			return err
+		}
+
+		utils.Logger.Debug().Msgf("resolved stages: %v", stageSlugs)
+	}
+
 	utils.Logger.Debug().Msg("copying repository to temp directory")
 
-	tmpDir, err := copyRepositoryDirToTempDir(repoDir)
+	tmpDir, err := copyRepositoryDirToTempDir(ctx, repoDir)
 	if err != nil {
 		return fmt.Errorf("make a repo temp copy: %w", err)
 	}
@@ -83,44 +122,97 @@ func TestCommand(shouldTestPrevious bool) (err error) {
 
 	utils.Logger.Debug().Msgf("committing changes to %s", tempBranchName)
 
-	tempCommitSha, err := commitChanges(tmpDir, fmt.Sprintf("CLI tests (%s)", tempBranchName))
+	tempCommitMessage := fmt.Sprintf("CLI tests (%s)", tempBranchName)
+
+	if isDryRun {
+		filesToCommit, err := listFilesToCommit(tmpDir)
+		if err != nil {
+			return fmt.Errorf("list files to commit: %w", err)
+		}
+
+		printDryRun(filesToCommit, tempCommitMessage)
+		return nil
+	}
+
+	tempCommitSha, err := commitChanges(tmpDir, tempCommitMessage)
 	if err != nil {
 		return fmt.Errorf("commit changes: %w", err)
 	}
 
 	// Place this before the push so that it "feels" fast
-	fmt.Println("Initiating test run...")
-	fmt.Println("")
+	output.Println("Initiating test run...")
+	output.Println("")
 
-	err = pushBranchToRemote(tmpDir, codecraftersRemote.Name)
+	err = pushBranchToRemote(ctx, tmpDir, codecraftersRemote.Name)
 	if err != nil {
 		return fmt.Errorf("push changes: %w", err)
 	}
 
 	utils.Logger.Debug().Msgf("pushed changes to remote branch %s", tempBranchName)
 
-	globals.SetCodecraftersServerURL(codecraftersRemote.CodecraftersServerURL())
-	codecraftersClient := client.NewCodecraftersClient()
-
 	utils.Logger.Debug().Msgf("creating submission for %s", tempCommitSha)
 
-	stageSelectionStrategy := "current_and_previous_descending"
-
-	if shouldTestPrevious {
-		stageSelectionStrategy = "current_and_previous_ascending"
-	}
-
-	createSubmissionResponse, err := codecraftersClient.CreateSubmission(codecraftersRemote.CodecraftersRepositoryId(), tempCommitSha, "test", stageSelectionStrategy)
+	createSubmissionResponse, err := codecraftersClient.CreateSubmission(ctx, codecraftersRemote.CodecraftersRepositoryId(), tempCommitSha, "test", stageSelectionStrategy, stageSlugs)
 	if err != nil {
 		return fmt.Errorf("create submission: %w", err)
 	}
 
 	utils.Logger.Debug().Msgf("submission created: %v", createSubmissionResponse.Id)
 
-	return handleSubmission(createSubmissionResponse, codecraftersClient)
+	output.Emit("submission_created", map[string]interface{}{"submission_id": createSubmissionResponse.Id, "commit_sha": tempCommitSha, "command": "test"})
+
+	recordSubmission(codecraftersRemote.CodecraftersRepositoryId(), history.Record{
+		SubmissionId:           createSubmissionResponse.Id,
+		CommitSha:              tempCommitSha,
+		Command:                "test",
+		StageSelectionStrategy: stageSelectionStrategy,
+		StageSlugs:             stageSlugs,
+		Status:                 "evaluating",
+		CreatedAt:              time.Now(),
+	})
+
+	return handleSubmission(ctx, createSubmissionResponse, codecraftersClient)
+}
+
+func resolveTestStageSlugs(ctx context.Context, codecraftersClient client.CodecraftersClient, repositoryId string, stageArg string, stageRangeArg string) ([]string, error) {
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, repositoryId)
+	if err != nil {
+		return nil, fmt.Errorf("fetch stage list: %w", err)
+	}
+
+	currentStageIndex := stageListResponse.CurrentStageIndex()
+	if currentStageIndex == -1 {
+		// All stages are complete, offsets are relative to the last stage
+		currentStageIndex = len(stageListResponse.Stages) - 1
+	}
+
+	if stageRangeArg != "" {
+		startIndex, endIndex, err := resolveStageRange(stageListResponse.Stages, currentStageIndex, stageRangeArg)
+		if err != nil {
+			return nil, err
+		}
+
+		stageSlugs := []string{}
+		for i := startIndex; i <= endIndex; i++ {
+			stageSlugs = append(stageSlugs, stageListResponse.Stages[i].Slug)
+		}
+
+		return stageSlugs, nil
+	}
+
+	stageIndex, err := resolveStageIndex(stageListResponse.Stages, currentStageIndex, stageArg)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{stageListResponse.Stages[stageIndex].Slug}, nil
 }
 
-func copyRepositoryDirToTempDir(repoDir string) (string, error) {
+// copyRepositoryDirToTempDir copies repoDir (without ignored files) to a new temp directory. The copy stops if ctx is
+// cancelled. The temp directory is removed if the copy fails, otherwise it's up to the caller to remove it.
+func copyRepositoryDirToTempDir(ctx context.Context, repoDir string) (string, error) {
 	tmpDir, err := os.MkdirTemp("", "codecrafters")
 
 	if err != nil {
@@ -130,9 +222,16 @@ func copyRepositoryDirToTempDir(repoDir string) (string, error) {
 	gitIgnore := utils.NewGitIgnore(repoDir)
 
 	err = cp.Copy(repoDir, tmpDir, cp.Options{
-		Skip: gitIgnore.SkipFile,
+		Skip: func(src string) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
+			return gitIgnore.SkipFile(src)
+		},
 	})
 	if err != nil {
+		os.RemoveAll(tmpDir)
 		return "", fmt.Errorf("copy files: %w", err)
 	}
 
@@ -178,8 +277,8 @@ func commitChanges(tmpDir string, commitMessage string) (string, error) {
 	return strings.TrimSpace(string(outputBytes)), nil
 }
 
-func pushBranchToRemote(tmpDir string, remoteName string) error {
-	outputBytes, err := exec.Command("git", "-C", tmpDir, "push", remoteName, "HEAD").CombinedOutput()
+func pushBranchToRemote(ctx context.Context, tmpDir string, remoteName string) error {
+	outputBytes, err := exec.CommandContext(ctx, "git", "-C", tmpDir, "push", remoteName, "HEAD").CombinedOutput()
 	if err != nil {
 		return wrapError(err, outputBytes, "run git command")
 	}
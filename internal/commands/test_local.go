@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/actions"
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/output"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+)
+
+// testerTestCase is the format testers expect in CODECRAFTERS_TEST_CASES_JSON
+type testerTestCase struct {
+	Slug            string `json:"slug"`
+	TesterLogPrefix string `json:"tester_log_prefix"`
+	Title           string `json:"title"`
+}
+
+// TestLocalCommand runs a locally installed tester against a snapshot of the repository, without pushing anything.
+// Stages are selected the same way as in TestCommand.
+func TestLocalCommand(ctx context.Context, testerPath string, shouldTestPrevious bool, stageArg string, stageRangeArg string) (err error) {
+	utils.Logger.Debug().Msg("local test command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("local test command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		var exitErr actions.ExitError
+		if errors.As(err, &exitErr) {
+			// Tests failed, which has already been reported
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	testerPath, err = filepath.Abs(testerPath)
+	if err != nil {
+		return fmt.Errorf("resolve tester path: %w", err)
+	}
+
+	if _, err := os.Stat(testerPath); err != nil {
+		return fmt.Errorf("Tester not found at %s.", testerPath)
+	}
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	stages, currentStageIndex, err := fetchStageListForLocalTest(ctx, codecraftersRemote)
+	if err != nil {
+		return err
+	}
+
+	var stageIndices []int
+
+	switch {
+	case stageRangeArg != "":
+		startIndex, endIndex, err := resolveStageRange(stages, currentStageIndex, stageRangeArg)
+		if err != nil {
+			return err
+		}
+
+		for i := startIndex; i <= endIndex; i++ {
+			stageIndices = append(stageIndices, i)
+		}
+	case stageArg != "":
+		stageIndex, err := resolveStageIndex(stages, currentStageIndex, stageArg)
+		if err != nil {
+			return err
+		}
+
+		stageIndices = []int{stageIndex}
+	case shouldTestPrevious:
+		for i := 0; i <= currentStageIndex; i++ {
+			stageIndices = append(stageIndices, i)
+		}
+	default:
+		// Mirrors the "current_and_previous_descending" strategy used for remote test runs
+		for i := currentStageIndex; i >= 0; i-- {
+			stageIndices = append(stageIndices, i)
+		}
+	}
+
+	testCases := []testerTestCase{}
+	for _, stageIndex := range stageIndices {
+		testCases = append(testCases, testerTestCase{
+			Slug:            stages[stageIndex].Slug,
+			TesterLogPrefix: fmt.Sprintf("tester::#%s", strings.ToUpper(stages[stageIndex].Slug)),
+			Title:           fmt.Sprintf("Stage #%d: %s", stageIndex+1, stages[stageIndex].Name),
+		})
+	}
+
+	testCasesJson, err := json.Marshal(testCases)
+	if err != nil {
+		return fmt.Errorf("encode test cases: %w", err)
+	}
+
+	utils.Logger.Debug().Msg("copying repository to temp directory")
+
+	tmpDir, err := copyRepositoryDirToTempDir(ctx, repoDir)
+	if err != nil {
+		return fmt.Errorf("make a repo temp copy: %w", err)
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	utils.Logger.Debug().Msgf("copied repository to temp directory: %s", tmpDir)
+
+	output.Println("Running tests locally...")
+	output.Println("")
+
+	testsPassed, err := runLocalTester(ctx, testerPath, tmpDir, string(testCasesJson))
+	if err != nil {
+		return fmt.Errorf("run tester: %w", err)
+	}
+
+	output.Println("")
+
+	if !testsPassed {
+		if err := (actions.PrintMessageAction{Color: "red", Text: "Tests failed."}).Execute(ctx); err != nil {
+			return err
+		}
+
+		return actions.ExitError{ExitCode: 1}
+	}
+
+	return actions.PrintMessageAction{Color: "green", Text: "Tests passed."}.Execute(ctx)
+}
+
+// fetchStageListForLocalTest fetches the stage list, falling back to the locally cached copy when offline
+func fetchStageListForLocalTest(ctx context.Context, codecraftersRemote utils.GitRemote) ([]client.Stage, int, error) {
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchStageList(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId())
+	if err != nil && ctx.Err() != nil {
+		return nil, -1, ctx.Err()
+	}
+
+	if err != nil {
+		utils.Logger.Debug().Err(err).Msg("failed to fetch stage list, using cached stage list")
+
+		stageListResponse, err = loadCachedStageList(codecraftersRemote.CodecraftersRepositoryId())
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("failed to load cached stage list")
+
+			return nil, -1, fmt.Errorf("Couldn't fetch the list of stages, and no cached copy is available.\nRun `codecrafters stages` once while online to cache it.")
+		}
+	}
+
+	currentStageIndex := stageListResponse.CurrentStageIndex()
+	if currentStageIndex == -1 {
+		// All stages are complete, test all of them
+		currentStageIndex = len(stageListResponse.Stages) - 1
+	}
+
+	return stageListResponse.Stages, currentStageIndex, nil
+}
+
+// runLocalTester runs the tester against repositoryDir and streams its output. It returns true if all tests passed.
+func runLocalTester(ctx context.Context, testerPath string, repositoryDir string, testCasesJson string) (bool, error) {
+	logsReader, logsWriter := io.Pipe()
+
+	cmd := exec.CommandContext(ctx, testerPath)
+	cmd.Dir = filepath.Dir(testerPath)
+	cmd.Env = append(os.Environ(),
+		"CODECRAFTERS_REPOSITORY_DIR="+repositoryDir,
+		"CODECRAFTERS_TEST_CASES_JSON="+testCasesJson,
+	)
+	cmd.Stdout = logsWriter
+	cmd.Stderr = logsWriter
+
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	streamLogsErrCh := make(chan error, 1)
+	go func() {
+		streamLogsErrCh <- actions.StreamLogs(logsReader)
+	}()
+
+	waitErr := cmd.Wait()
+	logsWriter.Close()
+
+	if err := <-streamLogsErrCh; err != nil {
+		return false, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		// The tester was killed, which isn't a test failure
+		return false, err
+	}
+
+	if waitErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(waitErr, &exitErr) {
+			return false, nil
+		}
+
+		return false, waitErr
+	}
+
+	return true, nil
+}
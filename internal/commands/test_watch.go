@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/fatih/color"
+	"github.com/getsentry/sentry-go"
+)
+
+// How often the repository is scanned for changes
+const watchPollInterval = 250 * time.Millisecond
+
+// How long the repository must stay unchanged before a new run starts, so that a burst of saves triggers a single run
+const watchDebounceInterval = 500 * time.Millisecond
+
+// TestWatchCommand runs `codecrafters test` with testArgs, and re-runs it whenever a file in the repository changes.
+//
+// Each run happens in a child process so that an in-flight run can be cancelled when a newer change arrives.
+func TestWatchCommand(ctx context.Context, testArgs []string) (err error) {
+	utils.Logger.Debug().Msg("test watch command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("test watch command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	// Fail early instead of printing the same error on every run
+	if _, err := identifyGitRemote(repoDir); err != nil {
+		return err
+	}
+
+	executablePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find executable: %w", err)
+	}
+
+	watcher, err := utils.NewFileWatcher(repoDir)
+	if err != nil {
+		return fmt.Errorf("watch files: %w", err)
+	}
+
+	changesCh := make(chan struct{}, 1)
+	go watchForChanges(watcher, changesCh)
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		runDoneCh := make(chan struct{})
+
+		go func() {
+			runTestProcess(runCtx, executablePath, repoDir, testArgs)
+			close(runDoneCh)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// The test run gets the interrupt too, wait for it to clean up
+			cancel()
+			<-runDoneCh
+
+			return ctx.Err()
+		case <-changesCh:
+			utils.Logger.Debug().Msg("change detected during run, cancelling")
+			cancel()
+			<-runDoneCh
+
+			fmt.Println("")
+			fmt.Println(yellow("Change detected, restarting test run..."))
+			fmt.Println("")
+		case <-runDoneCh:
+			cancel()
+
+			fmt.Println("")
+			fmt.Println(yellow("Watching for changes... (press Ctrl-C to exit)"))
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-changesCh:
+			}
+
+			fmt.Println("")
+			fmt.Println(yellow("Change detected, running tests..."))
+			fmt.Println("")
+		}
+	}
+}
+
+// watchForChanges sends to changesCh once the repository has changed and then stayed unchanged for watchDebounceInterval.
+func watchForChanges(watcher *utils.FileWatcher, changesCh chan<- struct{}) {
+	var lastChangeAt time.Time
+
+	for {
+		time.Sleep(watchPollInterval)
+
+		changed, err := watcher.Poll()
+		if err != nil {
+			utils.Logger.Debug().Err(err).Msg("failed to poll for changes")
+			continue
+		}
+
+		if changed {
+			lastChangeAt = time.Now()
+			continue
+		}
+
+		if lastChangeAt.IsZero() || time.Since(lastChangeAt) < watchDebounceInterval {
+			continue
+		}
+
+		lastChangeAt = time.Time{}
+
+		select {
+		case changesCh <- struct{}{}:
+		default: // A change is already pending
+		}
+	}
+}
+
+func runTestProcess(ctx context.Context, executablePath string, repoDir string, testArgs []string) {
+	cmd := exec.CommandContext(ctx, executablePath, append([]string{"test"}, testArgs...)...)
+	cmd.Dir = repoDir // Global flags like -C aren't passed on
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Interrupt first so that the run can clean up its temp directory, kill if that isn't supported (Windows)
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+
+		return nil
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	// A failing test run exits with a non-zero code, that's expected
+	if err := cmd.Run(); err != nil {
+		utils.Logger.Debug().Err(err).Msg("test run exited")
+	}
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/ui"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/getsentry/sentry-go"
+	"github.com/mattn/go-isatty"
+)
+
+// UICommand starts a full-screen interface to browse stage instructions, and run tests or submit from the same terminal
+func UICommand(ctx context.Context) (err error) {
+	utils.Logger.Debug().Msg("ui command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("ui command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil {
+			return
+		}
+
+		var noRepo utils.NoCodecraftersRemoteFoundError
+		if errors.Is(err, &noRepo) {
+			// ignore
+			return
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted, see main
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("`codecrafters ui` must be run in a terminal. Use `codecrafters task` and `codecrafters test` instead.")
+	}
+
+	utils.Logger.Debug().Msg("computing repository directory")
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("found repository directory: %s", repoDir)
+
+	utils.Logger.Debug().Msg("identifying remotes")
+
+	codecraftersRemote, err := identifyGitRemote(repoDir)
+	if err != nil {
+		return err
+	}
+
+	utils.Logger.Debug().Msgf("identified remote: %s, %s", codecraftersRemote.Name, codecraftersRemote.Url)
+
+	globals.SetCodecraftersServerURL(codecraftersServerURL(codecraftersRemote))
+	codecraftersClient := client.NewCodecraftersClient()
+
+	fetchRepositoryStageList := func() (client.FetchStageListResponse, error) {
+		return fetchStageList(ctx, codecraftersClient, codecraftersRemote.CodecraftersRepositoryId())
+	}
+
+	utils.Logger.Debug().Msg("fetching stage list")
+
+	stageListResponse, err := fetchRepositoryStageList()
+	if err != nil {
+		return fmt.Errorf("fetch stage list: %w", err)
+	}
+
+	executablePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find executable: %w", err)
+	}
+
+	glamourStyle := "light"
+	if lipgloss.HasDarkBackground() {
+		glamourStyle = "dark"
+	}
+
+	return ui.Run(ctx, ui.Options{
+		StageListResponse: stageListResponse,
+		FetchStageList:    fetchRepositoryStageList,
+		ExecutablePath:    executablePath,
+		RepoDir:           repoDir,
+		GlamourStyle:      glamourStyle,
+	})
+}
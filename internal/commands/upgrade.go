@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/codecrafters-io/cli/internal/config"
+	"github.com/codecrafters-io/cli/internal/globals"
+	"github.com/codecrafters-io/cli/internal/utils"
+	"github.com/fatih/color"
+	"github.com/getsentry/sentry-go"
+	"github.com/levigross/grequests"
+)
+
+// Used when the upgrade command isn't run from a CodeCrafters repository
+const defaultCodecraftersServerURL = "https://backend.codecrafters.io"
+
+// UpgradeCommand replaces the running binary with the latest release, if it's newer
+func UpgradeCommand(ctx context.Context, assumeYes bool) (err error) {
+	utils.Logger.Debug().Msg("upgrade command starts")
+	defer func() {
+		utils.Logger.Debug().Err(err).Msg("upgrade command ends")
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Logger.Panic().Str("panic", fmt.Sprintf("%v", p)).Stack().Msg("panic")
+			sentry.CurrentHub().Recover(p)
+
+			panic(p)
+		}
+
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		sentry.CurrentHub().CaptureException(err)
+	}()
+
+	currentVersionNumber, err := strconv.Atoi(utils.Version)
+	if err != nil || currentVersionNumber == 0 {
+		return fmt.Errorf("This is a development build (%s), it can't be upgraded. Install a release with install.sh instead.", utils.VersionString())
+	}
+
+	globals.SetCodecraftersServerURL(upgradeServerURL())
+	codecraftersClient := client.NewCodecraftersClient()
+
+	latestVersion, err := codecraftersClient.FetchLatestCLIVersion(ctx, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	latestVersionNumber, err := strconv.Atoi(strings.TrimPrefix(latestVersion.Version, "v"))
+	if err != nil {
+		return fmt.Errorf("unexpected latest version: %q", latestVersion.Version)
+	}
+
+	if latestVersionNumber <= currentVersionNumber {
+		fmt.Printf("You're already on the latest version (%s).\n", utils.VersionString())
+		return nil
+	}
+
+	executablePath, err := currentExecutablePath()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirm(fmt.Sprintf("Upgrade from v%d to %s?", currentVersionNumber, latestVersion.Version), assumeYes)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return fmt.Errorf("Aborted, the CLI wasn't upgraded.")
+	}
+
+	fmt.Printf("Downloading %s...\n", latestVersion.Version)
+
+	utils.Logger.Debug().Msgf("downloading %s", latestVersion.DownloadUrl)
+
+	response, err := grequests.Get(latestVersion.DownloadUrl, &grequests.RequestOptions{HTTPClient: client.HTTPClient(), Context: ctx})
+	if err != nil {
+		return fmt.Errorf("Failed to download %s: %s", latestVersion.DownloadUrl, err)
+	}
+
+	if !response.Ok {
+		return fmt.Errorf("Failed to download %s (status code: %d).", latestVersion.DownloadUrl, response.StatusCode)
+	}
+
+	archive := response.Bytes()
+
+	checksum := sha256.Sum256(archive)
+	if actualChecksum := hex.EncodeToString(checksum[:]); !strings.EqualFold(actualChecksum, latestVersion.ChecksumSHA256) {
+		return fmt.Errorf("Checksum mismatch for %s (expected %s, got %s). The download might be corrupted, please try again.", latestVersion.DownloadUrl, latestVersion.ChecksumSHA256, actualChecksum)
+	}
+
+	binaryName := "codecrafters"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+
+	binary, err := utils.ExtractFileFromTarGz(archive, binaryName)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.ReplaceExecutable(executablePath, binary, verifyExecutable); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("Permission denied while replacing %s. Run `sudo codecrafters upgrade`, or re-install with install.sh.", executablePath)
+		}
+
+		return fmt.Errorf("Upgrade failed: %s", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Println(green(fmt.Sprintf("Upgraded to %s.", latestVersion.Version)))
+
+	return nil
+}
+
+// upgradeServerURL returns the server set in config, or the one of the current repository, or the default one
+func upgradeServerURL() string {
+	if serverUrl := config.Get("server_url"); serverUrl != "" {
+		return serverUrl
+	}
+
+	repoDir, err := utils.GetRepositoryDir()
+	if err != nil {
+		return defaultCodecraftersServerURL
+	}
+
+	codecraftersRemote, err := utils.IdentifyGitRemote(repoDir)
+	if err != nil {
+		return defaultCodecraftersServerURL
+	}
+
+	return codecraftersServerURL(codecraftersRemote)
+}
+
+// currentExecutablePath returns the path of the running binary, with symlinks resolved so that the link isn't replaced
+func currentExecutablePath() (string, error) {
+	executablePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("find executable: %w", err)
+	}
+
+	executablePath, err = filepath.EvalSymlinks(executablePath)
+	if err != nil {
+		return "", fmt.Errorf("find executable: %w", err)
+	}
+
+	return executablePath, nil
+}
+
+// verifyExecutable checks that the binary at path runs and reports a version
+func verifyExecutable(path string) error {
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return fmt.Errorf("run %s --version: %w", path, err)
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(string(output)), "v") {
+		return fmt.Errorf("unexpected output from %s --version: %q", path, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
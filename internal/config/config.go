@@ -0,0 +1,416 @@
+// Package config resolves CLI settings. Values are read from (highest precedence first) CODECRAFTERS_* environment
+// variables, the repository's config file, the global config file and built-in defaults.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/cli/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+type Setting struct {
+	Key         string
+	Default     string
+	Description string
+
+	// AllowedValues restricts the values this setting accepts, if set
+	AllowedValues []string
+}
+
+var Settings = []Setting{
+	{
+		Key:           "assume_yes",
+		Default:       "false",
+		Description:   "Answer yes to confirmation prompts, like passing --yes",
+		AllowedValues: []string{"true", "false"},
+	},
+	{
+		Key:         "ca_bundle",
+		Default:     "",
+		Description: "PEM file with extra root certificates to trust, e.g. for a TLS-intercepting proxy",
+	},
+	{
+		Key:           "color",
+		Default:       "auto",
+		Description:   "When to use colors in output",
+		AllowedValues: []string{"auto", "always", "never"},
+	},
+	{
+		Key:           "log_level",
+		Default:       "info",
+		Description:   "Minimum level of log messages to print",
+		AllowedValues: []string{"trace", "debug", "info", "warn", "error"},
+	},
+	{
+		Key:         "remote",
+		Default:     "",
+		Description: "Git remote to use when the repository has several CodeCrafters remotes",
+	},
+	{
+		Key:         "server_url",
+		Default:     "",
+		Description: "CodeCrafters server URL to use, instead of the one inferred from the git remote",
+	},
+	{
+		Key:         "submit.branch",
+		Default:     "master",
+		Description: "Branch that `codecrafters submit` must be run from",
+	},
+	{
+		Key:         "submit.commit_message",
+		Default:     "codecrafters submit",
+		Description: "Commit message used by `codecrafters submit` when -m isn't passed",
+	},
+}
+
+// Sections of the config files that map names to URLs, rather than holding a single setting. They're edited by hand:
+//
+//	servers:
+//	  local: http://localhost:4000
+//	git_hosts:
+//	  git.mirror.example.com: https://backend.mirror.example.com
+const (
+	// ServersSection holds named servers, that can be selected with --server
+	ServersSection = "servers"
+
+	// GitHostsSection maps the hosts of git remotes to the CodeCrafters server they belong to
+	GitHostsSection = "git_hosts"
+)
+
+// Sources a value can come from, in order of precedence
+const (
+	SourceEnv     = "env"
+	SourceLocal   = "local"
+	SourceGlobal  = "global"
+	SourceDefault = "default"
+)
+
+type Config struct {
+	GlobalPath string
+
+	// LocalPath is empty when not running inside a repository
+	LocalPath string
+
+	globalValues map[string]interface{}
+	localValues  map[string]interface{}
+
+	// unreadablePaths are the config files that couldn't be parsed. Their settings are ignored.
+	unreadablePaths map[string]bool
+}
+
+var current = &Config{}
+
+// Load reads the global config file, and the repository's config file if repoDir isn't empty. The result is used by Get.
+//
+// Invalid config files are reported in the returned error, but the config is loaded regardless (ignoring the files that
+// can't be parsed), so that `codecrafters config` can still be used to fix them.
+func Load(repoDir string) error {
+	config := &Config{unreadablePaths: map[string]bool{}}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return fmt.Errorf("find config dir: %w", err)
+	}
+
+	config.GlobalPath = filepath.Join(userConfigDir, "codecrafters", "config.yml")
+
+	if repoDir != "" {
+		gitDir, err := utils.GetGitDir(repoDir)
+		if err != nil {
+			return err
+		}
+
+		// Inside the git dir, so that local settings are never committed
+		config.LocalPath = filepath.Join(gitDir, "codecrafters", "config.yml")
+	}
+
+	errs := []error{}
+
+	if config.globalValues, err = readFile(config.GlobalPath); err != nil {
+		errs = append(errs, err)
+		config.globalValues = map[string]interface{}{}
+		config.unreadablePaths[config.GlobalPath] = true
+	}
+
+	if config.localValues, err = readFile(config.LocalPath); err != nil {
+		errs = append(errs, err)
+		config.localValues = map[string]interface{}{}
+		config.unreadablePaths[config.LocalPath] = true
+	}
+
+	// Invalid sections are ignored by section(), and kept as they are when the file is updated
+	for _, section := range []string{ServersSection, GitHostsSection} {
+		if err := checkSection(config.globalValues, section, config.GlobalPath); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := checkSection(config.localValues, section, config.LocalPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	current = config
+
+	return errors.Join(errs...)
+}
+
+// Current returns the config loaded by Load
+func Current() *Config {
+	return current
+}
+
+// Get returns the resolved value of a setting. It panics if key isn't a known setting.
+func Get(key string) string {
+	value, _ := current.Lookup(key)
+	return value
+}
+
+// GetBool returns the resolved value of a boolean setting. Values that aren't booleans (e.g. from an environment
+// variable) are treated as false.
+func GetBool(key string) bool {
+	value, err := strconv.ParseBool(Get(key))
+	return err == nil && value
+}
+
+// Servers returns the servers that can be selected with --server, by name. Local servers override global ones.
+func Servers() map[string]string {
+	return current.section(ServersSection)
+}
+
+// GitHosts returns the CodeCrafters server URL of each configured git host. Local mappings override global ones.
+func GitHosts() map[string]string {
+	return current.section(GitHostsSection)
+}
+
+// ResolveServer returns the URL of a server from the servers section. URLs are returned as is, so that a server can be
+// used without adding it to the config first.
+func ResolveServer(name string) (string, error) {
+	if strings.Contains(name, "://") {
+		return name, nil
+	}
+
+	servers := Servers()
+
+	if serverUrl, ok := servers[name]; ok {
+		return serverUrl, nil
+	}
+
+	names := []string{}
+	for serverName := range servers {
+		names = append(names, serverName)
+	}
+
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return "", fmt.Errorf("Unknown server '%s'. Add it to the %s section of %s, or pass a URL.", name, ServersSection, current.GlobalPath)
+	}
+
+	return "", fmt.Errorf("Unknown server '%s'. Available servers: %s.", name, strings.Join(names, ", "))
+}
+
+func (c *Config) section(name string) map[string]string {
+	entries := map[string]string{}
+
+	for _, values := range []map[string]interface{}{c.globalValues, c.localValues} {
+		section, _ := values[name].(map[string]interface{})
+
+		for key, value := range section {
+			entries[key] = fmt.Sprint(value)
+		}
+	}
+
+	return entries
+}
+
+// Lookup returns the resolved value of a setting, and which source it came from
+func (c *Config) Lookup(key string) (string, string) {
+	setting := mustFindSetting(key)
+
+	if value, ok := os.LookupEnv(EnvVarName(key)); ok {
+		return value, SourceEnv
+	}
+
+	if value, ok := c.localValues[key]; ok {
+		return fmt.Sprint(value), SourceLocal
+	}
+
+	if value, ok := c.globalValues[key]; ok {
+		return fmt.Sprint(value), SourceGlobal
+	}
+
+	return setting.Default, SourceDefault
+}
+
+// Set writes a setting to the global config file, or the repository's config file if isLocal is set
+//
+// If that file couldn't be parsed, it's moved to <path>.bak and replaced by a file with only this setting.
+func (c *Config) Set(key string, value string, isLocal bool) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+
+	setting := mustFindSetting(key)
+	if len(setting.AllowedValues) > 0 && !contains(setting.AllowedValues, value) {
+		return fmt.Errorf("Invalid value '%s' for %s. Expected one of: %s.", value, key, strings.Join(setting.AllowedValues, ", "))
+	}
+
+	return c.updateFile(isLocal, func(values map[string]interface{}) {
+		values[key] = value
+	})
+}
+
+// Unset removes a setting from the global config file, or the repository's config file if isLocal is set. Like Set,
+// it replaces a file that couldn't be parsed.
+func (c *Config) Unset(key string, isLocal bool) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+
+	return c.updateFile(isLocal, func(values map[string]interface{}) {
+		delete(values, key)
+	})
+}
+
+func (c *Config) updateFile(isLocal bool, update func(values map[string]interface{})) error {
+	path := c.GlobalPath
+	values := c.globalValues
+
+	if isLocal {
+		if c.LocalPath == "" {
+			return fmt.Errorf("--local can only be used from within a repository.")
+		}
+
+		path = c.LocalPath
+		values = c.localValues
+	}
+
+	if c.unreadablePaths[path] {
+		// Keep the file that couldn't be parsed around rather than overwriting it
+		if err := os.Rename(path, path+".bak"); err != nil {
+			return fmt.Errorf("back up invalid config file: %w", err)
+		}
+	}
+
+	update(values)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	return nil
+}
+
+// EnvVarName returns the environment variable that overrides a setting, e.g. CODECRAFTERS_SUBMIT_BRANCH for submit.branch
+func EnvVarName(key string) string {
+	return "CODECRAFTERS_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func FindSetting(key string) (Setting, bool) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+func mustFindSetting(key string) Setting {
+	setting, ok := FindSetting(key)
+	if !ok {
+		panic(fmt.Sprintf("unknown setting: %s", key))
+	}
+
+	return setting
+}
+
+// CheckKey returns an error listing all settings if key isn't a known setting
+func CheckKey(key string) error {
+	if _, ok := FindSetting(key); ok {
+		return nil
+	}
+
+	keys := []string{}
+	for _, setting := range Settings {
+		keys = append(keys, setting.Key)
+	}
+
+	sort.Strings(keys)
+
+	return fmt.Errorf("Unknown setting '%s'. Available settings: %s.", key, strings.Join(keys, ", "))
+}
+
+func readFile(path string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	if path == "" {
+		return values, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	// An empty file decodes to a nil map
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	return values, nil
+}
+
+func checkSection(values map[string]interface{}, name string, path string) error {
+	value, ok := values[name]
+	if !ok {
+		return nil
+	}
+
+	section, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Invalid %s section in %s. Expected a map of names to URLs.", name, path)
+	}
+
+	for key, entry := range section {
+		if _, ok := entry.(string); !ok {
+			return fmt.Errorf("Invalid value for %s in the %s section of %s. Expected a URL.", key, name, path)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
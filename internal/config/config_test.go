@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRepository(t *testing.T) string {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "--quiet", repoDir).Run())
+
+	return repoDir
+}
+
+func TestLookupPrecedence(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+
+	value, source := Current().Lookup("submit.branch")
+	assert.Equal(t, "master", value)
+	assert.Equal(t, SourceDefault, source)
+
+	require.NoError(t, Current().Set("submit.branch", "global-branch", false))
+	require.NoError(t, Load(repoDir))
+
+	value, source = Current().Lookup("submit.branch")
+	assert.Equal(t, "global-branch", value)
+	assert.Equal(t, SourceGlobal, source)
+
+	require.NoError(t, Current().Set("submit.branch", "local-branch", true))
+	require.NoError(t, Load(repoDir))
+
+	value, source = Current().Lookup("submit.branch")
+	assert.Equal(t, "local-branch", value)
+	assert.Equal(t, SourceLocal, source)
+
+	t.Setenv("CODECRAFTERS_SUBMIT_BRANCH", "env-branch")
+
+	value, source = Current().Lookup("submit.branch")
+	assert.Equal(t, "env-branch", value)
+	assert.Equal(t, SourceEnv, source)
+}
+
+func TestLocalConfigIsInsideGitDir(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+
+	resolvedRepoDir, err := filepath.EvalSymlinks(repoDir)
+	require.NoError(t, err)
+
+	resolvedLocalPath, err := filepath.EvalSymlinks(filepath.Dir(filepath.Dir(Current().LocalPath)))
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(resolvedRepoDir, ".git"), resolvedLocalPath)
+}
+
+func TestLoadWithoutRepository(t *testing.T) {
+	setupRepository(t)
+
+	require.NoError(t, Load(""))
+	assert.Empty(t, Current().LocalPath)
+
+	assert.Error(t, Current().Set("submit.branch", "main", true))
+}
+
+func TestUnset(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+	require.NoError(t, Current().Set("color", "never", false))
+	require.NoError(t, Current().Unset("color", false))
+	require.NoError(t, Load(repoDir))
+
+	value, source := Current().Lookup("color")
+	assert.Equal(t, "auto", value)
+	assert.Equal(t, SourceDefault, source)
+}
+
+func TestSetValidation(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+
+	assert.Error(t, Current().Set("color", "sometimes", false))
+	assert.Error(t, Current().Set("unknown", "value", false))
+
+	_, err := os.Stat(Current().GlobalPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "CODECRAFTERS_SUBMIT_COMMIT_MESSAGE", EnvVarName("submit.commit_message"))
+	assert.Equal(t, "CODECRAFTERS_LOG_LEVEL", EnvVarName("log_level"))
+}
+
+func TestGetBool(t *testing.T) {
+	require.NoError(t, Load(setupRepository(t)))
+
+	assert.False(t, GetBool("assume_yes"))
+
+	for value, expected := range map[string]bool{"1": true, "true": true, "TRUE": true, "0": false, "no": false, "": false} {
+		t.Setenv("CODECRAFTERS_ASSUME_YES", value)
+		assert.Equal(t, expected, GetBool("assume_yes"), "value: %q", value)
+	}
+}
+
+func TestServersAndGitHosts(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+	require.NoError(t, os.MkdirAll(filepath.Dir(Current().GlobalPath), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(Current().LocalPath), 0755))
+
+	require.NoError(t, os.WriteFile(Current().GlobalPath, []byte("servers:\n  local: http://localhost:4000\n  mirror: https://backend.mirror.example.com\ngit_hosts:\n  git.mirror.example.com: https://backend.mirror.example.com\n"), 0644))
+	require.NoError(t, os.WriteFile(Current().LocalPath, []byte("servers:\n  local: http://localhost:5000\n"), 0644))
+	require.NoError(t, Load(repoDir))
+
+	assert.Equal(t, map[string]string{"local": "http://localhost:5000", "mirror": "https://backend.mirror.example.com"}, Servers())
+	assert.Equal(t, map[string]string{"git.mirror.example.com": "https://backend.mirror.example.com"}, GitHosts())
+
+	serverUrl, err := ResolveServer("local")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:5000", serverUrl)
+
+	serverUrl, err = ResolveServer("http://127.0.0.1:3000")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:3000", serverUrl)
+
+	_, err = ResolveServer("staging")
+	assert.EqualError(t, err, "Unknown server 'staging'. Available servers: local, mirror.")
+
+	// Settings can still be changed, without losing the sections
+	require.NoError(t, Current().Set("color", "never", false))
+	require.NoError(t, Load(repoDir))
+	assert.Len(t, Servers(), 2)
+}
+
+func TestInvalidSection(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+	require.NoError(t, os.MkdirAll(filepath.Dir(Current().GlobalPath), 0755))
+	require.NoError(t, os.WriteFile(Current().GlobalPath, []byte("servers:\n  - http://localhost:4000\n"), 0644))
+
+	assert.ErrorContains(t, Load(repoDir), "Invalid servers section")
+}
+
+func TestUnparseableConfigFile(t *testing.T) {
+	repoDir := setupRepository(t)
+
+	require.NoError(t, Load(repoDir))
+	require.NoError(t, Current().Set("color", "never", true))
+	require.NoError(t, os.MkdirAll(filepath.Dir(Current().GlobalPath), 0755))
+	require.NoError(t, os.WriteFile(Current().GlobalPath, []byte("color: [never\n"), 0644))
+
+	// The other files are still loaded
+	assert.ErrorContains(t, Load(repoDir), "parse config file "+Current().GlobalPath)
+	assert.Equal(t, "never", Get("color"))
+
+	// Changing a setting replaces the file, keeping a backup of it
+	require.NoError(t, Current().Set("log_level", "debug", false))
+	require.NoError(t, Load(repoDir))
+	assert.Equal(t, "debug", Get("log_level"))
+
+	backup, err := os.ReadFile(Current().GlobalPath + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, "color: [never\n", string(backup))
+}
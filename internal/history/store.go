@@ -0,0 +1,123 @@
+// Package history stores a local record of the submissions created by `test` and `submit`
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/cli/internal/utils"
+)
+
+type Record struct {
+	SubmissionId           string   `json:"submission_id"`
+	CommitSha              string   `json:"commit_sha"`
+	Command                string   `json:"command"`
+	StageSelectionStrategy string   `json:"stage_selection_strategy"`
+	StageSlugs             []string `json:"stage_slugs,omitempty"`
+
+	// Status is "evaluating" until the submission reaches a terminal status ("success" or "failure")
+	Status string `json:"status"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (r Record) Duration() (time.Duration, bool) {
+	if r.FinishedAt == nil {
+		return 0, false
+	}
+
+	return r.FinishedAt.Sub(r.CreatedAt), true
+}
+
+// maxRecordSize is the longest line Records reads, well above the size of any record
+const maxRecordSize = 16 * 1024 * 1024
+
+// Store is an append-only log of records for a single repository. Updating a record appends a new version of it, the
+// latest version wins when reading.
+type Store struct {
+	path string
+}
+
+func OpenStore(repositoryId string) (Store, error) {
+	dataDir, err := utils.UserDataDir()
+	if err != nil {
+		return Store{}, fmt.Errorf("find data dir: %w", err)
+	}
+
+	return Store{path: filepath.Join(dataDir, "codecrafters", "history", repositoryId+".jsonl")}, nil
+}
+
+func (s Store) Append(record Record) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+
+	recordJson, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode history record: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+
+	defer file.Close()
+
+	if _, err := file.Write(append(recordJson, '\n')); err != nil {
+		return fmt.Errorf("write history record: %w", err)
+	}
+
+	return nil
+}
+
+// Records returns the latest version of every record, most recent first
+func (s Store) Records() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return []Record{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("open history file: %w", err)
+	}
+
+	defer file.Close()
+
+	recordsBySubmissionId := map[string]Record{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Skip lines that were only partially written
+			utils.Logger.Debug().Err(err).Msg("skipping invalid history record")
+			continue
+		}
+
+		recordsBySubmissionId[record.SubmissionId] = record
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history file: %w", err)
+	}
+
+	records := []Record{}
+	for _, record := range recordsBySubmissionId {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+
+	return records, nil
+}
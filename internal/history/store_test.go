@@ -0,0 +1,65 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	store, err := OpenStore("dummy")
+	assert.NoError(t, err)
+
+	t.Run("without history file", func(t *testing.T) {
+		records, err := store.Records()
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("latest version of each record wins, most recent first", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Minute)
+		firstRecord := Record{SubmissionId: "1", Command: "test", Status: "evaluating", CreatedAt: createdAt}
+		secondRecord := Record{SubmissionId: "2", Command: "submit", Status: "evaluating", CreatedAt: createdAt.Add(time.Second)}
+
+		assert.NoError(t, store.Append(firstRecord))
+		assert.NoError(t, store.Append(secondRecord))
+
+		finishedAt := createdAt.Add(5 * time.Second)
+		firstRecord.Status = "success"
+		firstRecord.FinishedAt = &finishedAt
+		assert.NoError(t, store.Append(firstRecord))
+
+		records, err := store.Records()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+
+		assert.Equal(t, "2", records[0].SubmissionId)
+		assert.Equal(t, "evaluating", records[0].Status)
+		_, hasDuration := records[0].Duration()
+		assert.False(t, hasDuration)
+
+		assert.Equal(t, "1", records[1].SubmissionId)
+		assert.Equal(t, "success", records[1].Status)
+		duration, hasDuration := records[1].Duration()
+		assert.True(t, hasDuration)
+		assert.Equal(t, 5*time.Second, duration)
+	})
+	t.Run("records longer than the default scanner buffer", func(t *testing.T) {
+		stageSlugs := make([]string, 20_000)
+		for i := range stageSlugs {
+			stageSlugs[i] = "abc"
+		}
+
+		largeRecord := Record{SubmissionId: "3", Command: "test", StageSlugs: stageSlugs, Status: "evaluating", CreatedAt: time.Now()}
+		assert.NoError(t, store.Append(largeRecord))
+
+		records, err := store.Records()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "3", records[0].SubmissionId)
+		assert.Len(t, records[0].StageSlugs, 20_000)
+	})
+}
@@ -0,0 +1,118 @@
+// Package output writes what commands report to the user. By default that's text, but with `--output json` every
+// message, log line and status change is written to stdout as a newline-delimited JSON event instead, for tools that
+// wrap the CLI.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var (
+	format                    = FormatText
+	writer          io.Writer = os.Stdout
+	writerMutex     sync.Mutex
+	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// SetFormat switches between text (the default) and json output
+func SetFormat(newFormat string) error {
+	if newFormat != FormatText && newFormat != FormatJSON {
+		return fmt.Errorf("Invalid output format '%s'. Expected text or json.", newFormat)
+	}
+
+	format = newFormat
+
+	return nil
+}
+
+// IsJSON reports whether output should be written as JSON events
+func IsJSON() bool {
+	return format == FormatJSON
+}
+
+// Emit writes an event with the given type and fields as a single JSON line. It does nothing in text mode, so it's
+// safe to call for events that have no text equivalent (e.g. a submission being created).
+func Emit(eventType string, fields map[string]interface{}) {
+	if !IsJSON() {
+		return
+	}
+
+	event := map[string]interface{}{}
+	for key, value := range fields {
+		event[key] = value
+	}
+
+	event["type"] = eventType
+
+	eventJson, err := json.Marshal(event)
+	if err != nil {
+		// Fields are always plain values, this can't happen
+		panic(fmt.Sprintf("failed to marshal %s event: %v", eventType, err))
+	}
+
+	writerMutex.Lock()
+	defer writerMutex.Unlock()
+
+	writer.Write(append(eventJson, '\n'))
+}
+
+// Println prints text followed by a newline. In json mode it's emitted as a plain message event, blank lines are
+// dropped since they're only there for spacing.
+func Println(text string) {
+	if !IsJSON() {
+		fmt.Println(text)
+		return
+	}
+
+	EmitMessage("plain", text)
+}
+
+// Printf is like fmt.Printf, with the same json handling as Println
+func Printf(textFormat string, args ...interface{}) {
+	if !IsJSON() {
+		fmt.Printf(textFormat, args...)
+		return
+	}
+
+	EmitMessage("plain", fmt.Sprintf(textFormat, args...))
+}
+
+// EmitMessage emits a message event, with surrounding whitespace and ANSI escape codes removed
+func EmitMessage(color string, text string) {
+	text = strings.TrimSpace(StripANSI(text))
+	if text == "" {
+		return
+	}
+
+	Emit("message", map[string]interface{}{"color": color, "text": text})
+}
+
+// EmitResult emits the final event of a command. Tools can rely on it being the last line of output.
+func EmitResult(exitCode int, err error) {
+	fields := map[string]interface{}{
+		"success":   exitCode == 0,
+		"exit_code": exitCode,
+	}
+
+	if err != nil && err.Error() != "" {
+		fields["error"] = err.Error()
+	}
+
+	Emit("result", fields)
+}
+
+// StripANSI removes ANSI escape codes (colors, cursor movement etc.) from text
+func StripANSI(text string) string {
+	return ansiEscapeRegex.ReplaceAllString(text, "")
+}
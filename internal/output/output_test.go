@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureEvents(t *testing.T, outputFormat string) *bytes.Buffer {
+	var buffer bytes.Buffer
+
+	previousFormat, previousWriter := format, writer
+	t.Cleanup(func() {
+		format, writer = previousFormat, previousWriter
+	})
+
+	require.NoError(t, SetFormat(outputFormat))
+	writer = &buffer
+
+	return &buffer
+}
+
+func parseEvents(t *testing.T, buffer *bytes.Buffer) []map[string]interface{} {
+	events := []map[string]interface{}{}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n") {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "line: %s", line)
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestEmitInTextMode(t *testing.T) {
+	buffer := captureEvents(t, FormatText)
+
+	Emit("submission_created", map[string]interface{}{"submission_id": "123"})
+
+	assert.Empty(t, buffer.String())
+}
+
+func TestEmitInJSONMode(t *testing.T) {
+	buffer := captureEvents(t, FormatJSON)
+
+	Emit("submission_status", map[string]interface{}{"submission_id": "123", "status": "success"})
+	EmitMessage("red", "\033[31mTests failed.\033[0m\n")
+	EmitMessage("plain", "")
+	Printf("Submitting changes (commit: %s)...\n\n", "abc1234")
+	EmitResult(1, fmt.Errorf("push changes: rejected"))
+
+	events := parseEvents(t, buffer)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, map[string]interface{}{"type": "submission_status", "submission_id": "123", "status": "success"}, events[0])
+	assert.Equal(t, map[string]interface{}{"type": "message", "color": "red", "text": "Tests failed."}, events[1])
+	assert.Equal(t, "Submitting changes (commit: abc1234)...", events[2]["text"])
+	assert.Equal(t, map[string]interface{}{"type": "result", "success": false, "exit_code": float64(1), "error": "push changes: rejected"}, events[3])
+}
+
+func TestEmitResultWithoutMessage(t *testing.T) {
+	buffer := captureEvents(t, FormatJSON)
+
+	// Errors without a message are used for failures that have already been reported
+	EmitResult(1, fmt.Errorf(""))
+
+	events := parseEvents(t, buffer)
+	require.Len(t, events, 1)
+	assert.NotContains(t, events[0], "error")
+}
+
+func TestSetFormat(t *testing.T) {
+	captureEvents(t, FormatText)
+
+	assert.Error(t, SetFormat("yaml"))
+	assert.False(t, IsJSON())
+}
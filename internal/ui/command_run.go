@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commandRun is a `codecrafters` command running in a child process, with its output streamed line by line
+type commandRun struct {
+	name string
+	cmd  *exec.Cmd
+
+	outputCh chan string
+
+	// doneCh is closed once the command has exited, with its result in err
+	doneCh chan struct{}
+	err    error
+}
+
+type commandOutputMsg struct {
+	run  *commandRun
+	line string
+}
+
+type commandFinishedMsg struct {
+	run *commandRun
+	err error
+}
+
+func startCommandRun(name string, executablePath string, repoDir string, args []string) (*commandRun, error) {
+	cmd := exec.Command(executablePath, args...)
+	cmd.Dir = repoDir
+
+	// The output isn't a terminal, but it's displayed in one
+	cmd.Env = append(os.Environ(), "CODECRAFTERS_COLOR=always")
+
+	outputReader, outputWriter := io.Pipe()
+	cmd.Stdout = outputWriter
+	cmd.Stderr = outputWriter
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	run := &commandRun{
+		name:     name,
+		cmd:      cmd,
+		outputCh: make(chan string),
+		doneCh:   make(chan struct{}),
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(outputReader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			run.outputCh <- scanner.Text()
+		}
+
+		if err := scanner.Err(); err != nil {
+			run.outputCh <- fmt.Sprintf("[Output not shown: %s]", err)
+
+			// The command can't exit while it's blocked writing to the pipe
+			io.Copy(io.Discard, outputReader)
+		}
+
+		close(run.outputCh)
+	}()
+
+	go func() {
+		run.err = cmd.Wait()
+		outputWriter.Close()
+		close(run.doneCh)
+	}()
+
+	return run, nil
+}
+
+// cancel interrupts the command, so that it can clean up like it does on Ctrl-C
+func (r *commandRun) cancel() {
+	if err := r.cmd.Process.Signal(os.Interrupt); err != nil {
+		r.cmd.Process.Kill()
+	}
+}
+
+// stop interrupts the command and waits for it to exit, killing it if it takes longer than gracePeriod. It's used once
+// the UI has quit, when nothing displays the output anymore.
+func (r *commandRun) stop(gracePeriod time.Duration) {
+	r.cancel()
+
+	// The command can't exit while it's blocked writing output
+	go func() {
+		for range r.outputCh {
+		}
+	}()
+
+	select {
+	case <-r.doneCh:
+	case <-time.After(gracePeriod):
+		r.cmd.Process.Kill()
+		<-r.doneCh
+	}
+}
+
+// waitForCommandOutput returns the next line of output, or commandFinishedMsg once the command has exited
+func waitForCommandOutput(run *commandRun) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-run.outputCh
+		if ok {
+			return commandOutputMsg{run: run, line: line}
+		}
+
+		<-run.doneCh
+
+		return commandFinishedMsg{run: run, err: run.err}
+	}
+}
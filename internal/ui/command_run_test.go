@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startScript runs script as if it were the codecrafters executable
+func startScript(t *testing.T, script string) *commandRun {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	executablePath := filepath.Join(t.TempDir(), "codecrafters")
+	require.NoError(t, os.WriteFile(executablePath, []byte("#!/bin/sh\n"+script), 0755))
+
+	run, err := startCommandRun("Tests", executablePath, t.TempDir(), []string{"test"})
+	require.NoError(t, err)
+
+	return run
+}
+
+// collectOutput returns the lines a run outputs and how it finished, as the UI receives them
+func collectOutput(t *testing.T, run *commandRun) ([]string, commandFinishedMsg) {
+	lines := []string{}
+
+	timeout := time.After(10 * time.Second)
+
+	for {
+		msgCh := make(chan interface{}, 1)
+		go func() { msgCh <- waitForCommandOutput(run)() }()
+
+		select {
+		case msg := <-msgCh:
+			switch msg := msg.(type) {
+			case commandOutputMsg:
+				lines = append(lines, msg.line)
+			case commandFinishedMsg:
+				return lines, msg
+			}
+		case <-timeout:
+			require.FailNow(t, "command didn't finish")
+		}
+	}
+}
+
+func TestCommandRunStreamsOutput(t *testing.T) {
+	run := startScript(t, "echo \"running $1\"\necho oops >&2\nexit 3\n")
+
+	lines, finishedMsg := collectOutput(t, run)
+	assert.Equal(t, []string{"running test", "oops"}, lines)
+	assert.EqualError(t, finishedMsg.err, "exit status 3")
+}
+
+func TestCommandRunFinishesAfterOverlongLines(t *testing.T) {
+	run := startScript(t, "head -c 2000000 /dev/zero | tr '\\0' x\necho\necho after\n")
+
+	lines, finishedMsg := collectOutput(t, run)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "Output not shown")
+	assert.NoError(t, finishedMsg.err)
+}
+
+func TestCommandRunStop(t *testing.T) {
+	t.Run("waits for the command to clean up", func(t *testing.T) {
+		cleanedUpPath := filepath.Join(t.TempDir(), "cleaned-up")
+		run := startScript(t, "trap 'sleep 0.2; touch "+cleanedUpPath+"; exit 130' INT\necho started\nwhile true; do sleep 0.05; done\n")
+
+		// Nothing reads the output once the UI has quit
+		<-run.outputCh
+
+		run.stop(5 * time.Second)
+		assert.FileExists(t, cleanedUpPath)
+	})
+
+	t.Run("kills the command after the grace period", func(t *testing.T) {
+		run := startScript(t, "trap '' INT\necho started\nwhile true; do sleep 0.05; done\n")
+		<-run.outputCh
+
+		startedAt := time.Now()
+		run.stop(200 * time.Millisecond)
+		assert.Less(t, time.Since(startedAt), 5*time.Second)
+	})
+}
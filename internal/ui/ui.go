@@ -0,0 +1,397 @@
+// Package ui implements `codecrafters ui`, a full-screen terminal interface for browsing stages and running tests.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codecrafters-io/cli/internal/client"
+)
+
+type Options struct {
+	StageListResponse client.FetchStageListResponse
+
+	// FetchStageList is used to refresh the stage list after a run, e.g. to move to the next stage after a submission
+	FetchStageList func() (client.FetchStageListResponse, error)
+
+	// ExecutablePath and RepoDir are used to run `codecrafters test` and `codecrafters submit` in child processes
+	ExecutablePath string
+	RepoDir        string
+
+	// GlamourStyle is the glamour style used for instructions. It's detected before starting the UI, since querying the
+	// terminal's background color doesn't work once the UI has taken over the terminal.
+	GlamourStyle string
+}
+
+type pane int
+
+const (
+	instructionsPane pane = iota
+	logsPane
+)
+
+const stageListWidth = 32
+
+// stopGracePeriod is how long a running command has to clean up once the UI quits, see commandRun.stop
+const stopGracePeriod = 3 * time.Second
+
+var (
+	selectedStageStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+	currentStageStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	activeTabStyle     = lipgloss.NewStyle().Bold(true).Underline(true)
+	inactiveTabStyle   = lipgloss.NewStyle().Faint(true)
+	helpStyle          = lipgloss.NewStyle().Faint(true)
+	borderStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, true, false, false).PaddingRight(1)
+)
+
+type model struct {
+	options Options
+
+	stages             []client.Stage
+	selectedStageIndex int
+
+	width  int
+	height int
+
+	activePane           pane
+	instructionsViewport viewport.Model
+	logsViewport         viewport.Model
+	logLines             []string
+
+	run                *commandRun
+	runStatus          string
+	isConfirmingSubmit bool
+}
+
+type stageListRefreshedMsg struct {
+	stageListResponse client.FetchStageListResponse
+	err               error
+}
+
+// Run starts the UI, and returns once the user quits or ctx is cancelled
+func Run(ctx context.Context, options Options) error {
+	m := &model{
+		options:              options,
+		stages:               options.StageListResponse.Stages,
+		selectedStageIndex:   max(options.StageListResponse.CurrentStageIndex(), 0),
+		instructionsViewport: viewport.New(0, 0),
+		logsViewport:         viewport.New(0, 0),
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if m.run != nil {
+		m.run.stop(stopGracePeriod)
+	}
+
+	return err
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resizeViewports()
+		m.renderInstructions()
+		m.renderLogs()
+
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	case commandOutputMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+
+		m.appendLogLine(msg.line)
+
+		return m, waitForCommandOutput(msg.run)
+	case commandFinishedMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+
+		m.run = nil
+
+		if msg.err != nil {
+			m.runStatus = fmt.Sprintf("%s failed (%s)", msg.run.name, msg.err)
+		} else {
+			m.runStatus = fmt.Sprintf("%s passed", msg.run.name)
+		}
+
+		return m, m.refreshStageList()
+	case stageListRefreshedMsg:
+		if msg.err != nil {
+			m.runStatus = fmt.Sprintf("%s, failed to refresh stages: %s", m.runStatus, msg.err)
+			return m, nil
+		}
+
+		m.stages = msg.stageListResponse.Stages
+		if m.selectedStageIndex >= len(m.stages) {
+			m.selectedStageIndex = max(len(m.stages)-1, 0)
+		}
+
+		m.renderInstructions()
+
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.isConfirmingSubmit {
+		m.isConfirmingSubmit = false
+
+		if msg.String() == "y" {
+			return m, m.startCommand("Submission", []string{"submit"})
+		}
+
+		m.runStatus = "Submission cancelled"
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.selectedStageIndex > 0 {
+			m.selectedStageIndex--
+			m.renderInstructions()
+		}
+	case "down", "j":
+		if m.selectedStageIndex < len(m.stages)-1 {
+			m.selectedStageIndex++
+			m.renderInstructions()
+		}
+	case "tab":
+		if m.activePane == instructionsPane {
+			m.activePane = logsPane
+		} else {
+			m.activePane = instructionsPane
+		}
+	case "pgup", "ctrl+u":
+		m.activeViewport().HalfPageUp()
+	case "pgdown", "ctrl+d", " ":
+		m.activeViewport().HalfPageDown()
+	case "home", "g":
+		m.activeViewport().GotoTop()
+	case "end", "G":
+		m.activeViewport().GotoBottom()
+	case "t":
+		if len(m.stages) == 0 {
+			return m, nil
+		}
+
+		stage := m.stages[m.selectedStageIndex]
+		return m, m.startCommand(fmt.Sprintf("Tests for #%s", stage.Slug), []string{"test", "--stage", stage.Slug})
+	case "T":
+		return m, m.startCommand("Tests for previous stages", []string{"test", "--previous"})
+	case "s":
+		if m.run == nil {
+			m.isConfirmingSubmit = true
+		}
+	case "x":
+		if m.run != nil {
+			m.run.cancel()
+			m.runStatus = fmt.Sprintf("Cancelling %s...", strings.ToLower(m.run.name))
+		}
+	}
+
+	return m, nil
+}
+
+func (m *model) activeViewport() *viewport.Model {
+	if m.activePane == logsPane {
+		return &m.logsViewport
+	}
+
+	return &m.instructionsViewport
+}
+
+func (m *model) startCommand(name string, args []string) tea.Cmd {
+	if m.run != nil {
+		m.runStatus = fmt.Sprintf("%s is still running, press x to cancel it", m.run.name)
+		return nil
+	}
+
+	run, err := startCommandRun(name, m.options.ExecutablePath, m.options.RepoDir, args)
+	if err != nil {
+		m.runStatus = fmt.Sprintf("Failed to start %s: %s", strings.ToLower(name), err)
+		return nil
+	}
+
+	m.run = run
+	m.runStatus = fmt.Sprintf("%s running...", name)
+	m.activePane = logsPane
+	m.logLines = []string{}
+	m.renderLogs()
+
+	return waitForCommandOutput(run)
+}
+
+func (m *model) refreshStageList() tea.Cmd {
+	return func() tea.Msg {
+		stageListResponse, err := m.options.FetchStageList()
+		return stageListRefreshedMsg{stageListResponse: stageListResponse, err: err}
+	}
+}
+
+func (m *model) appendLogLine(line string) {
+	m.logLines = append(m.logLines, line)
+
+	// Follow the output, unless the user scrolled up to read earlier logs
+	shouldFollow := m.logsViewport.AtBottom()
+	m.renderLogs()
+
+	if shouldFollow {
+		m.logsViewport.GotoBottom()
+	}
+}
+
+func (m *model) resizeViewports() {
+	// One line for the tabs, and one for the help line
+	viewportHeight := max(m.height-2, 1)
+	viewportWidth := max(m.width-stageListWidth-3, 10)
+
+	m.instructionsViewport.Width, m.instructionsViewport.Height = viewportWidth, viewportHeight
+	m.logsViewport.Width, m.logsViewport.Height = viewportWidth, viewportHeight
+}
+
+func (m *model) renderInstructions() {
+	if len(m.stages) == 0 || m.instructionsViewport.Width == 0 {
+		m.instructionsViewport.SetContent("No stages found.")
+		return
+	}
+
+	markdown := m.stages[m.selectedStageIndex].GetDocsMarkdown()
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(m.options.GlamourStyle),
+		glamour.WithWordWrap(m.instructionsViewport.Width-2),
+	)
+	if err != nil {
+		m.instructionsViewport.SetContent(markdown)
+		return
+	}
+
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		m.instructionsViewport.SetContent(markdown)
+		return
+	}
+
+	m.instructionsViewport.SetContent(rendered)
+	m.instructionsViewport.GotoTop()
+}
+
+func (m *model) renderLogs() {
+	if len(m.logLines) == 0 {
+		m.logsViewport.SetContent("No test runs yet. Press t to test the selected stage.")
+		return
+	}
+
+	m.logsViewport.SetContent(strings.Join(m.logLines, "\n"))
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	stageList := borderStyle.Height(m.height - 1).Render(m.viewStageList())
+
+	tabs := m.viewTab("Instructions", instructionsPane) + "  " + m.viewTab("Logs", logsPane)
+
+	var content string
+	if m.activePane == logsPane {
+		content = m.logsViewport.View()
+	} else {
+		content = m.instructionsViewport.View()
+	}
+
+	main := lipgloss.JoinVertical(lipgloss.Left, tabs, content)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, stageList, " ", main),
+		m.viewHelp(),
+	)
+}
+
+func (m *model) viewStageList() string {
+	visibleCount := max(m.height-1, 1)
+
+	// Scroll so that the selected stage is always visible
+	offset := 0
+	if m.selectedStageIndex >= visibleCount {
+		offset = m.selectedStageIndex - visibleCount + 1
+	}
+
+	lines := []string{}
+
+	for i := offset; i < len(m.stages) && i < offset+visibleCount; i++ {
+		stage := m.stages[i]
+
+		marker := "  "
+		if stage.IsCurrent {
+			marker = "→ "
+		}
+
+		line := truncate(fmt.Sprintf("%s%d. %s", marker, i+1, stage.Name), stageListWidth)
+		line += strings.Repeat(" ", max(stageListWidth-lipgloss.Width(line), 0))
+
+		switch {
+		case i == m.selectedStageIndex:
+			line = selectedStageStyle.Render(line)
+		case stage.IsCurrent:
+			line = currentStageStyle.Render(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) viewTab(title string, tabPane pane) string {
+	if m.activePane == tabPane {
+		return activeTabStyle.Render(title)
+	}
+
+	return inactiveTabStyle.Render(title)
+}
+
+func (m *model) viewHelp() string {
+	if m.isConfirmingSubmit {
+		return "Commit all changes and submit? (y/n)"
+	}
+
+	help := "↑/↓ select stage • t test stage • T test previous stages • s submit • tab switch pane • pgup/pgdn scroll • x cancel • q quit"
+	if m.runStatus != "" {
+		help = m.runStatus + " • " + help
+	}
+
+	return helpStyle.Render(truncate(help, m.width))
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	if width <= 1 {
+		return string(runes[:width])
+	}
+
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codecrafters-io/cli/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModel(t *testing.T) *model {
+	stageListResponse := client.FetchStageListResponse{Stages: []client.Stage{
+		{Slug: "oo8", Name: "Bind to a port"},
+		{Slug: "cz2", Name: "Respond with 200", IsCurrent: true},
+		{Slug: "ff0", Name: "Extract URL path"},
+	}}
+
+	m := &model{
+		options: Options{
+			StageListResponse: stageListResponse,
+			FetchStageList: func() (client.FetchStageListResponse, error) {
+				return stageListResponse, nil
+			},
+			GlamourStyle: "dark",
+		},
+		stages:               stageListResponse.Stages,
+		selectedStageIndex:   stageListResponse.CurrentStageIndex(),
+		instructionsViewport: viewport.New(0, 0),
+		logsViewport:         viewport.New(0, 0),
+	}
+
+	update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	return m
+}
+
+func update(t *testing.T, m *model, msg tea.Msg) tea.Cmd {
+	updatedModel, cmd := m.Update(msg)
+	require.Same(t, m, updatedModel)
+
+	return cmd
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+}
+
+func TestModelSelectsStages(t *testing.T) {
+	m := newTestModel(t)
+	assert.Equal(t, 1, m.selectedStageIndex)
+	assert.Contains(t, m.instructionsViewport.View(), "Respond with 200")
+
+	update(t, m, keyMsg("down"))
+	assert.Equal(t, 2, m.selectedStageIndex)
+	assert.Contains(t, m.instructionsViewport.View(), "Extract URL path")
+
+	// Stays on the last stage
+	update(t, m, keyMsg("j"))
+	assert.Equal(t, 2, m.selectedStageIndex)
+
+	update(t, m, keyMsg("up"))
+	update(t, m, keyMsg("k"))
+	assert.Equal(t, 0, m.selectedStageIndex)
+
+	assert.Contains(t, m.View(), "Bind to a port")
+}
+
+func TestModelSwitchesPanes(t *testing.T) {
+	m := newTestModel(t)
+	assert.Equal(t, instructionsPane, m.activePane)
+
+	update(t, m, keyMsg("tab"))
+	assert.Equal(t, logsPane, m.activePane)
+	assert.Contains(t, m.View(), "No test runs yet")
+
+	update(t, m, keyMsg("tab"))
+	assert.Equal(t, instructionsPane, m.activePane)
+}
+
+func TestModelQuits(t *testing.T) {
+	m := newTestModel(t)
+
+	cmd := update(t, m, keyMsg("q"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestModelConfirmsSubmissions(t *testing.T) {
+	m := newTestModel(t)
+
+	update(t, m, keyMsg("s"))
+	assert.True(t, m.isConfirmingSubmit)
+	assert.Contains(t, m.View(), "Commit all changes and submit? (y/n)")
+
+	assert.Nil(t, update(t, m, keyMsg("n")))
+	assert.False(t, m.isConfirmingSubmit)
+	assert.Nil(t, m.run)
+	assert.Equal(t, "Submission cancelled", m.runStatus)
+}
+
+func TestModelShowsCommandOutput(t *testing.T) {
+	m := newTestModel(t)
+	run := &commandRun{name: "Tests for #cz2"}
+	m.run = run
+
+	update(t, m, commandOutputMsg{run: run, line: "remote: Running tests..."})
+	update(t, m, commandOutputMsg{run: run, line: "remote: Test passed."})
+	assert.Equal(t, []string{"remote: Running tests...", "remote: Test passed."}, m.logLines)
+
+	// Output from a previous run is ignored
+	update(t, m, commandOutputMsg{run: &commandRun{name: "Old run"}, line: "stale"})
+	assert.Len(t, m.logLines, 2)
+
+	m.activePane = logsPane
+	assert.Contains(t, m.View(), "remote: Test passed.")
+}
+
+func TestModelFinishesCommands(t *testing.T) {
+	m := newTestModel(t)
+	run := &commandRun{name: "Tests for #cz2"}
+	m.run = run
+
+	cmd := update(t, m, commandFinishedMsg{run: run})
+	assert.Nil(t, m.run)
+	assert.Equal(t, "Tests for #cz2 passed", m.runStatus)
+
+	// The stage list is refreshed, e.g. to move on to the next stage after a submission
+	require.NotNil(t, cmd)
+	update(t, m, cmd())
+	assert.Equal(t, "Tests for #cz2 passed", m.runStatus)
+
+	run = &commandRun{name: "Submission"}
+	m.run = run
+
+	update(t, m, commandFinishedMsg{run: run, err: errors.New("exit status 1")})
+	assert.Equal(t, "Submission failed (exit status 1)", m.runStatus)
+	assert.Contains(t, m.View(), "Submission failed (exit status 1)")
+}
+
+func TestModelDoesntStartTwoCommands(t *testing.T) {
+	m := newTestModel(t)
+	m.run = &commandRun{name: "Submission"}
+
+	assert.Nil(t, update(t, m, keyMsg("t")))
+	assert.Equal(t, "Submission is still running, press x to cancel it", m.runStatus)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Confirm asks a yes/no question on stdin, and returns true if the user answers yes. The question is written to out.
+//
+// If assumeYes is set (from --yes or CODECRAFTERS_ASSUME_YES) the question isn't asked. Otherwise stdin must be a
+// terminal, so that scripts fail instead of hanging on a prompt that no one will answer.
+func Confirm(question string, assumeYes bool, out io.Writer) (bool, error) {
+	return confirm(question, assumeYes, StdinIsTerminal(), os.Stdin, out)
+}
+
+func confirm(question string, assumeYes bool, isTerminal bool, in io.Reader, out io.Writer) (bool, error) {
+	if assumeYes {
+		fmt.Fprintf(out, "%s [y/N] y (assumed)\n", question)
+		return true, nil
+	}
+
+	if !isTerminal {
+		return false, fmt.Errorf("%s\nCan't ask for confirmation because stdin isn't a terminal. Pass --yes or set CODECRAFTERS_ASSUME_YES=1 to proceed without asking.", question)
+	}
+
+	fmt.Fprintf(out, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read user input: %w", err)
+	}
+
+	// Ctrl-D leaves the cursor on the prompt line
+	if err == io.EOF {
+		fmt.Fprintln(out)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Choose asks the user to pick one of options by number, and returns the index of the chosen option. Like Confirm, it
+// writes the question to out, and fails if stdin isn't a terminal.
+func Choose(question string, options []string, out io.Writer) (int, error) {
+	return choose(question, options, StdinIsTerminal(), os.Stdin, out)
+}
+
+func choose(question string, options []string, isTerminal bool, in io.Reader, out io.Writer) (int, error) {
+	if !isTerminal {
+		return -1, fmt.Errorf("%s\nCan't ask because stdin isn't a terminal.", question)
+	}
+
+	fmt.Fprintln(out, question)
+	fmt.Fprintln(out)
+
+	for i, option := range options {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, option)
+	}
+
+	fmt.Fprintln(out)
+
+	reader := bufio.NewReader(in)
+
+	for {
+		fmt.Fprintf(out, "Enter a number (1-%d): ", len(options))
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return -1, fmt.Errorf("failed to read user input: %w", err)
+		}
+
+		if err == io.EOF && strings.TrimSpace(answer) == "" {
+			fmt.Fprintln(out)
+			return -1, fmt.Errorf("Aborted, no option was chosen.")
+		}
+
+		number, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err == nil && number >= 1 && number <= len(options) {
+			return number - 1, nil
+		}
+
+		fmt.Fprintf(out, "'%s' isn't a valid choice.\n", strings.TrimSpace(answer))
+	}
+}
+
+// StdinIsTerminal reports whether the user can be prompted for input
+func StdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	for _, testCase := range []struct {
+		input    string
+		expected bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"anything\n", false},
+		{"", false}, // Ctrl-D
+	} {
+		var out bytes.Buffer
+
+		confirmed, err := confirm("Proceed?", false, true, strings.NewReader(testCase.input), &out)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, confirmed, "input: %q", testCase.input)
+		assert.Contains(t, out.String(), "Proceed? [y/N] ")
+	}
+}
+
+func TestConfirmAssumeYes(t *testing.T) {
+	var out bytes.Buffer
+
+	confirmed, err := confirm("Proceed?", true, false, strings.NewReader(""), &out)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	assert.Equal(t, "Proceed? [y/N] y (assumed)\n", out.String())
+}
+
+func TestConfirmWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+
+	confirmed, err := confirm("Proceed?", false, false, strings.NewReader("y\n"), &out)
+	assert.False(t, confirmed)
+	assert.ErrorContains(t, err, "--yes")
+	assert.Empty(t, out.String())
+}
+
+func TestChoose(t *testing.T) {
+	var out bytes.Buffer
+
+	index, err := choose("Which remote?", []string{"origin", "staging"}, true, strings.NewReader("3\nstaging\n2\n"), &out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, index)
+	assert.Contains(t, out.String(), "  2. staging\n")
+	assert.Contains(t, out.String(), "'3' isn't a valid choice.")
+
+	_, err = choose("Which remote?", []string{"origin", "staging"}, true, strings.NewReader(""), &out)
+	assert.EqualError(t, err, "Aborted, no option was chosen.")
+
+	_, err = choose("Which remote?", []string{"origin", "staging"}, false, strings.NewReader("1\n"), &out)
+	assert.Error(t, err)
+}
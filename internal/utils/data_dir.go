@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// UserDataDir returns the directory for user-specific data that should persist across runs. Unlike os.UserCacheDir,
+// data here isn't expected to be deleted by the OS.
+func UserDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		// %AppData% and ~/Library/Application Support
+		return os.UserConfigDir()
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".local", "share"), nil
+}
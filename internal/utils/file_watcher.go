@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// FileWatcher detects changes to files in a repository by polling. Files skipped by GitIgnore
+// (and anything inside .git) are not tracked. The rules are reloaded when .gitignore changes.
+type FileWatcher struct {
+	baseDir        string
+	gitIgnore      GitIgnore
+	gitIgnoreState fileState
+	snapshot       map[string]fileState
+}
+
+func NewFileWatcher(baseDir string) (*FileWatcher, error) {
+	watcher := &FileWatcher{
+		baseDir: baseDir,
+	}
+
+	snapshot, err := watcher.takeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher.snapshot = snapshot
+
+	return watcher, nil
+}
+
+// Poll returns true if any tracked file was added, removed or modified since the last call.
+func (w *FileWatcher) Poll() (bool, error) {
+	snapshot, err := w.takeSnapshot()
+	if err != nil {
+		return false, err
+	}
+
+	changed := len(snapshot) != len(w.snapshot)
+
+	if !changed {
+		for path, state := range snapshot {
+			previousState, ok := w.snapshot[path]
+			if !ok || previousState != state {
+				changed = true
+				break
+			}
+		}
+	}
+
+	w.snapshot = snapshot
+
+	return changed, nil
+}
+
+// reloadGitIgnore recompiles the ignore rules if .gitignore was added, removed or modified since they were compiled
+func (w *FileWatcher) reloadGitIgnore() {
+	var state fileState
+
+	// A missing .gitignore has the zero state
+	if info, err := os.Stat(filepath.Join(w.baseDir, ".gitignore")); err == nil {
+		state = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	if w.gitIgnore.baseDir != "" && state == w.gitIgnoreState {
+		return
+	}
+
+	w.gitIgnore = NewGitIgnore(w.baseDir)
+	w.gitIgnoreState = state
+}
+
+func (w *FileWatcher) takeSnapshot() (map[string]fileState, error) {
+	w.reloadGitIgnore()
+
+	snapshot := map[string]fileState{}
+
+	err := filepath.WalkDir(w.baseDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear between listing a directory and visiting them
+			if path != w.baseDir {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		skip, err := w.gitIgnore.SkipFile(path)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		snapshot[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileWatcher(t *testing.T) {
+	tmpRepoDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpRepoDir, ".gitignore"), "ignored.txt\nbuild/\n")
+	writeFile(t, filepath.Join(tmpRepoDir, "main.go"), "package main")
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpRepoDir, ".git"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpRepoDir, "build"), 0755))
+
+	watcher, err := NewFileWatcher(tmpRepoDir)
+	assert.NoError(t, err)
+
+	assertNotChanged := func() {
+		changed, err := watcher.Poll()
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	}
+
+	assertChanged := func() {
+		changed, err := watcher.Poll()
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	}
+
+	assertNotChanged()
+
+	t.Run("ignores changes to ignored files", func(t *testing.T) {
+		writeFile(t, filepath.Join(tmpRepoDir, "ignored.txt"), "ignored")
+		writeFile(t, filepath.Join(tmpRepoDir, "build", "output"), "binary")
+		assertNotChanged()
+	})
+
+	t.Run("ignores changes inside .git", func(t *testing.T) {
+		writeFile(t, filepath.Join(tmpRepoDir, ".git", "index"), "index")
+		assertNotChanged()
+	})
+
+	t.Run("detects modified files", func(t *testing.T) {
+		writeFile(t, filepath.Join(tmpRepoDir, "main.go"), "package main\n\nfunc main() {}")
+		assertChanged()
+		assertNotChanged()
+	})
+
+	t.Run("detects added files", func(t *testing.T) {
+		writeFile(t, filepath.Join(tmpRepoDir, "server.go"), "package main")
+		assertChanged()
+		assertNotChanged()
+	})
+
+	t.Run("detects removed files", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(tmpRepoDir, "server.go")))
+		assertChanged()
+		assertNotChanged()
+	})
+
+	t.Run("reloads .gitignore when it changes", func(t *testing.T) {
+		writeFile(t, filepath.Join(tmpRepoDir, "generated.txt"), "v1")
+		assertChanged()
+
+		// The .gitignore change itself is a change
+		writeFile(t, filepath.Join(tmpRepoDir, ".gitignore"), "ignored.txt\nbuild/\ngenerated.txt\n")
+		assertChanged()
+
+		writeFile(t, filepath.Join(tmpRepoDir, "generated.txt"), "v2")
+		assertNotChanged()
+	})
+}
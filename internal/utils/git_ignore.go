@@ -21,7 +21,7 @@ func NewGitIgnore(baseDir string) GitIgnore {
 	return GitIgnore{
 		baseDir:         baseDir,
 		localGitIgnore:  compileIgnorer(filepath.Join(baseDir, ".gitignore")),
-		globalGitIgnore: compileIgnorer(getGlobalGitIgnorePath()),
+		globalGitIgnore: compileIgnorer(GetGlobalGitIgnorePath()),
 		gitInfoExclude:  compileIgnorer(filepath.Join(baseDir, ".git", "info", "exclude")),
 	}
 }
@@ -56,7 +56,8 @@ func compileIgnorer(path string) *ignore.GitIgnore {
 	return ignorer
 }
 
-func getGlobalGitIgnorePath() string {
+// GetGlobalGitIgnorePath resolves git's core.excludesfile setting, returning an empty string if it isn't set
+func GetGlobalGitIgnorePath() string {
 	output, err := exec.Command("git", "config", "--get", "core.excludesfile").Output()
 	if err != nil {
 		return ""
@@ -116,7 +116,7 @@ func (b *FileBackup) Restore(t *testing.T) {
 }
 
 func setupGlobalGitIgnore(t *testing.T, content string) *FileBackup {
-	globalGitIgnorePath := getGlobalGitIgnorePath()
+	globalGitIgnorePath := GetGlobalGitIgnorePath()
 	backupPath := filepath.Join(t.TempDir(), ".gitignore_global")
 
 	if globalGitIgnorePath == "" {
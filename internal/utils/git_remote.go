@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"net/url"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -16,40 +17,117 @@ func (r GitRemote) IsCodecrafters() bool {
 	return r.CodecraftersServerURL() != ""
 }
 
+// gitHostServerURLs maps git hosts to CodeCrafters servers, in addition to the built-in ones (from the git_hosts
+// section of the config)
+var gitHostServerURLs = map[string]string{}
+
+func SetGitHostServerURLs(serverUrls map[string]string) {
+	gitHostServerURLs = map[string]string{}
+
+	for host, serverUrl := range serverUrls {
+		gitHostServerURLs[strings.ToLower(host)] = serverUrl
+	}
+}
+
+// Environment returns the kind of CodeCrafters server the remote belongs to: production, staging, development or
+// custom (for hosts from the git_hosts config)
+func (r GitRemote) Environment() string {
+	if _, host, _ := r.parse(); gitHostServerURLs[host] != "" {
+		return "custom"
+	}
+
+	switch r.CodecraftersServerURL() {
+	case "":
+		return ""
+	case "https://backend.codecrafters.io":
+		return "production"
+	case "https://backend-staging.codecrafters.io":
+		return "staging"
+	default:
+		return "development"
+	}
+}
+
+// CodecraftersRepositoryId returns the last segment of the remote's path, e.g. abc123 for
+// https://git.codecrafters.io/abc123.git
 func (r GitRemote) CodecraftersRepositoryId() string {
-	return strings.Split(r.Url, "/")[len(strings.Split(r.Url, "/"))-1]
+	_, _, path := r.parse()
+
+	path = strings.TrimRight(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	return path[strings.LastIndex(path, "/")+1:]
 }
 
+var (
+	ngrokDevGitHostRegex      = regexp.MustCompile(`^cc-([\w-]+)-git\.ngrok\.io$`)
+	cloudflareDevGitHostRegex = regexp.MustCompile(`^([\w-]+)-git\.ccdev\.dev$`)
+)
+
 func (r GitRemote) CodecraftersServerURL() string {
-	if strings.Contains(r.Url, "git.codecrafters.io") || strings.Contains(r.Url, "git2.codecrafters.io") {
-		return "https://backend.codecrafters.io"
+	scheme, host, _ := r.parse()
+	if host == "" {
+		return ""
 	}
 
-	if strings.Contains(r.Url, "git-staging.codecrafters.io") {
+	if serverUrl, ok := gitHostServerURLs[host]; ok {
+		return serverUrl
+	}
+
+	switch host {
+	case "git.codecrafters.io", "git2.codecrafters.io":
+		return "https://backend.codecrafters.io"
+	case "git-staging.codecrafters.io":
 		return "https://backend-staging.codecrafters.io"
 	}
 
-	ngrokDevServerRegex := regexp.MustCompile(`cc-([\w-]+).ngrok.io`)
+	// Development servers use the same scheme for git and the backend
+	if scheme != "http" {
+		scheme = "https"
+	}
 
 	// cc-paul-git.ngrok.io -> paul-backend.ccdev.dev
-	if ngrokDevServerRegex.MatchString(r.Url) {
-		replacedUrl := regexp.MustCompile(`\-git.ngrok.io/.*`).ReplaceAllString(r.Url, "-backend.ccdev.dev") // cc-paul-backend.ccdev.dev
-		replacedUrl = regexp.MustCompile("cc-").ReplaceAllString(replacedUrl, "")                            // paul-backend.ccdev.dev
-		return replacedUrl
+	if matches := ngrokDevGitHostRegex.FindStringSubmatch(host); matches != nil {
+		return fmt.Sprintf("%s://%s-backend.ccdev.dev", scheme, matches[1])
 	}
 
-	cloudflareDevServerRegex := regexp.MustCompile("(.*)-git.ccdev.dev")
-
-	// cc-paul-git.ccdev.dev -> paul.ccdev.dev
-	if cloudflareDevServerRegex.MatchString(r.Url) {
-		replacedUrl := regexp.MustCompile("-git").ReplaceAllString(r.Url, "-backend")
-		replacedUrl = regexp.MustCompile("ccdev.dev/.*").ReplaceAllString(replacedUrl, "ccdev.dev")
-		return replacedUrl
+	// cc-paul-git.ccdev.dev -> cc-paul-backend.ccdev.dev
+	if matches := cloudflareDevGitHostRegex.FindStringSubmatch(host); matches != nil {
+		return fmt.Sprintf("%s://%s-backend.ccdev.dev", scheme, matches[1])
 	}
 
 	return ""
 }
 
+// scpLikeRemoteUrlRegex matches addresses like git@host:path, which git treats as ssh://git@host/path
+var scpLikeRemoteUrlRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^@/:]+):(.*)$`)
+
+// parse splits the remote's URL into its scheme, (lowercased) host and path. Remotes are either URLs like
+// https://host/path and ssh://git@host/path, or scp-like addresses like git@host:path. Hosts are always matched
+// exactly, so that a remote like https://example.com/git.codecrafters.io isn't mistaken for a CodeCrafters remote. The
+// host is empty if the URL isn't a supported remote (e.g. a local path).
+func (r GitRemote) parse() (string, string, string) {
+	if strings.Contains(r.Url, "://") {
+		parsedUrl, err := url.Parse(r.Url)
+		if err != nil {
+			return "", "", ""
+		}
+
+		switch parsedUrl.Scheme {
+		case "https", "http", "ssh", "git+ssh", "ssh+git":
+			return parsedUrl.Scheme, strings.ToLower(parsedUrl.Hostname()), parsedUrl.Path
+		default:
+			return "", "", ""
+		}
+	}
+
+	if matches := scpLikeRemoteUrlRegex.FindStringSubmatch(r.Url); matches != nil {
+		return "ssh", strings.ToLower(matches[1]), matches[2]
+	}
+
+	return "", "", ""
+}
+
 type NoCodecraftersRemoteFoundError struct {
 	error
 	Remotes []GitRemote
@@ -74,7 +152,32 @@ func (e MultipleCodecraftersRemotesFoundError) Error() string {
 		remoteUrls = append(remoteUrls, remote.Url)
 	}
 
-	return "Multiple CodeCrafters git remotes found: " + strings.Join(remoteUrls, ", ")
+	return "Multiple CodeCrafters git remotes found: " + strings.Join(remoteUrls, ", ") + "\n" +
+		"Use --remote <name> to choose one, or `codecrafters config set --local remote <name>` to always use the same one."
+}
+
+type GitRemoteNotFoundError struct {
+	Name    string
+	Remotes []GitRemote
+}
+
+func (e GitRemoteNotFoundError) Error() string {
+	remoteNames := []string{}
+	for _, remote := range e.Remotes {
+		if remote.IsCodecrafters() {
+			remoteNames = append(remoteNames, remote.Name)
+		}
+	}
+
+	return fmt.Sprintf("No CodeCrafters git remote named '%s' found. CodeCrafters remotes: %s", e.Name, strings.Join(remoteNames, ", "))
+}
+
+// preferredRemoteName is the remote to use, from --remote or the remote setting. If empty, the only CodeCrafters remote
+// is used.
+var preferredRemoteName string
+
+func SetPreferredRemoteName(name string) {
+	preferredRemoteName = name
 }
 
 func IdentifyGitRemote(repositoryDir string) (GitRemote, error) {
@@ -83,6 +186,16 @@ func IdentifyGitRemote(repositoryDir string) (GitRemote, error) {
 		return GitRemote{}, err
 	}
 
+	if preferredRemoteName != "" {
+		for _, remote := range remotes {
+			if remote.Name == preferredRemoteName && remote.IsCodecrafters() {
+				return remote, nil
+			}
+		}
+
+		return GitRemote{}, GitRemoteNotFoundError{Name: preferredRemoteName, Remotes: remotes}
+	}
+
 	codecraftersRemotes := []GitRemote{}
 
 	for _, remote := range remotes {
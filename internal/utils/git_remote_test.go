@@ -44,6 +44,69 @@ func TestIdentifyGitRemoteWithSingleDevelopmentRemote(t *testing.T) {
 	assert.Equal(t, "https://paul-backend.ccdev.dev", remote.CodecraftersServerURL())
 }
 
+func TestCodecraftersServerURL(t *testing.T) {
+	for remoteUrl, expectedServerUrl := range map[string]string{
+		"https://git.codecrafters.io/abc123":             "https://backend.codecrafters.io",
+		"https://GIT.codecrafters.io/abc123":             "https://backend.codecrafters.io",
+		"https://git2.codecrafters.io/abc123":            "https://backend.codecrafters.io",
+		"ssh://git@git.codecrafters.io/abc123":           "https://backend.codecrafters.io",
+		"git@git.codecrafters.io:abc123":                 "https://backend.codecrafters.io",
+		"https://git-staging.codecrafters.io/abc123":     "https://backend-staging.codecrafters.io",
+		"https://cc-paul-git.ngrok.io/abc123":            "https://paul-backend.ccdev.dev",
+		"https://cc-paul-git.ccdev.dev/abc123":           "https://cc-paul-backend.ccdev.dev",
+		"http://cc-paul-git.ccdev.dev/abc123":            "http://cc-paul-backend.ccdev.dev",
+		"https://evil.example/git.codecrafters.io/x":     "",
+		"https://git.codecrafters.io.evil.example/x":     "",
+		"https://git.codecrafters.io@evil.example/x":     "",
+		"https://evil.example/?host=git.codecrafters.io": "",
+		"git@evil.example:git.codecrafters.io/x":         "",
+		"https://cc-paul-git.ngrok.io.evil.example/x":    "",
+		"https://evil.example/cc-paul-git.ccdev.dev":     "",
+		"file:///tmp/git.codecrafters.io/abc123":         "",
+		"/tmp/git.codecrafters.io/abc123":                "",
+		"https://github.com/codecrafters-io/abc123":      "",
+	} {
+		assert.Equal(t, expectedServerUrl, GitRemote{Url: remoteUrl}.CodecraftersServerURL(), "url: %s", remoteUrl)
+	}
+}
+
+func TestCodecraftersRepositoryId(t *testing.T) {
+	for _, remoteUrl := range []string{
+		"https://git.codecrafters.io/abc123",
+		"https://git.codecrafters.io/abc123.git",
+		"https://git.codecrafters.io/abc123/",
+		"https://git.codecrafters.io/abc123.git/",
+		"ssh://git@git.codecrafters.io/abc123.git",
+		"git@git.codecrafters.io:abc123.git",
+		"git@git.codecrafters.io:abc123",
+	} {
+		assert.Equal(t, "abc123", GitRemote{Url: remoteUrl}.CodecraftersRepositoryId(), "url: %s", remoteUrl)
+	}
+}
+
+func TestIdentifyGitRemoteWithConfiguredGitHost(t *testing.T) {
+	t.Cleanup(func() {
+		SetGitHostServerURLs(nil)
+	})
+
+	SetGitHostServerURLs(map[string]string{"Git.Mirror.example.com": "https://backend.mirror.example.com"})
+
+	for _, remoteUrl := range []string{
+		"https://git.mirror.example.com/dummy",
+		"ssh://git@git.mirror.example.com/dummy",
+		"git@git.mirror.example.com:dummy",
+	} {
+		repositoryDir := createEmptyRepository(t)
+		createRemote(t, repositoryDir, "origin", remoteUrl)
+
+		remote, err := IdentifyGitRemote(repositoryDir)
+		assert.Nil(t, err, "url: %s", remoteUrl)
+
+		assert.Equal(t, "https://backend.mirror.example.com", remote.CodecraftersServerURL())
+		assert.Equal(t, "custom", remote.Environment())
+	}
+}
+
 func TestIdentifyGitRemoteWithMultipleRemotes(t *testing.T) {
 	repositoryDir := createEmptyRepository(t)
 	createRemote(t, repositoryDir, "origin", "https://git.codecrafters.io/dummy1")
@@ -62,7 +125,32 @@ func TestIdentifyGitRemoteWithMultipleCodecraftersRemotes(t *testing.T) {
 	createRemote(t, repositoryDir, "origin2", "https://git.codecrafters.io/dummy2")
 
 	_, err := IdentifyGitRemote(repositoryDir)
-	assert.EqualError(t, err, "Multiple CodeCrafters git remotes found: https://git.codecrafters.io/dummy1, https://git.codecrafters.io/dummy2")
+	assert.EqualError(t, err, "Multiple CodeCrafters git remotes found: https://git.codecrafters.io/dummy1, https://git.codecrafters.io/dummy2\n"+
+		"Use --remote <name> to choose one, or `codecrafters config set --local remote <name>` to always use the same one.")
+}
+
+func TestIdentifyGitRemoteWithPreferredRemote(t *testing.T) {
+	t.Cleanup(func() {
+		SetPreferredRemoteName("")
+	})
+
+	repositoryDir := createEmptyRepository(t)
+	createRemote(t, repositoryDir, "production", "https://git.codecrafters.io/dummy1")
+	createRemote(t, repositoryDir, "staging", "https://git-staging.codecrafters.io/dummy2")
+	createRemote(t, repositoryDir, "github", "https://github.com/codecrafters-io/dummy3")
+
+	SetPreferredRemoteName("staging")
+
+	remote, err := IdentifyGitRemote(repositoryDir)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "staging", remote.Name)
+	assert.Equal(t, "staging", remote.Environment())
+
+	SetPreferredRemoteName("github")
+
+	_, err = IdentifyGitRemote(repositoryDir)
+	assert.EqualError(t, err, "No CodeCrafters git remote named 'github' found. CodeCrafters remotes: production, staging")
 }
 
 func TestIdentifyGitRemoteWithNoCodecraftersRemotes(t *testing.T) {
@@ -28,6 +28,7 @@ func newLogger() zerolog.Logger {
 	logger := zerolog.New(logWriter).With().Timestamp().Logger()
 	logger = logger.Level(zerolog.InfoLevel)
 
+	// The config isn't loaded yet, so the env var is read here too. This way, config loading itself can be debugged.
 	if q := os.Getenv("CODECRAFTERS_LOG_LEVEL"); q != "" {
 		lvl, err := zerolog.ParseLevel(q)
 		if err == nil {
@@ -39,3 +40,14 @@ func newLogger() zerolog.Logger {
 
 	return logger
 }
+
+// SetLogLevel changes the level of Logger, e.g. after the log_level setting is loaded
+func SetLogLevel(level string) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		Logger.Warn().Err(err).Msg("parse log level")
+		return
+	}
+
+	Logger = Logger.Level(lvl)
+}
@@ -5,20 +5,52 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
-func GetRepositoryDir() (string, error) {
-	dir, err := os.Getwd()
+// repositoryDirOverride is set by the global -C/--repo-dir flag, and used instead of the working directory
+var repositoryDirOverride string
+
+// SetRepositoryDir makes GetRepositoryDir look for the repository containing dir instead of the working directory
+func SetRepositoryDir(dir string) error {
+	absoluteDir, err := filepath.Abs(dir)
 	if err != nil {
-		return "", fmt.Errorf("get workdir: %w", err)
+		return fmt.Errorf("resolve %s: %w", dir, err)
+	}
+
+	info, err := os.Stat(absoluteDir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("Directory '%s' doesn't exist.", dir)
+	}
+
+	repositoryDirOverride = absoluteDir
+
+	return nil
+}
+
+func GetRepositoryDir() (string, error) {
+	dir := repositoryDirOverride
+
+	if dir == "" {
+		var err error
+
+		dir, err = os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("get workdir: %w", err)
+		}
 	}
 
 	outputBytes, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").CombinedOutput()
 	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
 			if regexp.MustCompile("not a git repository").Match(outputBytes) {
+				if repositoryDirOverride != "" {
+					return "", fmt.Errorf(`Error: %s is not within a Git repository.
+Please pass the path of your CodeCrafters Git repository to -C.`, repositoryDirOverride)
+				}
+
 				return "", errors.New(`Error: The current directory is not within a Git repository.
 Please run this command from within your CodeCrafters Git repository.`)
 			}
@@ -29,3 +61,19 @@ Please run this command from within your CodeCrafters Git repository.`)
 
 	return strings.TrimSpace(string(outputBytes)), nil
 }
+
+// GetGitDir returns the absolute path of the repository's git directory. This is usually <repoDir>/.git, but not in
+// worktrees or submodules.
+func GetGitDir(repoDir string) (string, error) {
+	outputBytes, err := exec.Command("git", "-C", repoDir, "rev-parse", "--git-dir").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run 'git rev-parse' to get git dir. err: %v.\n%s", err, string(outputBytes))
+	}
+
+	gitDir := strings.TrimSpace(string(outputBytes))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoDir, gitDir)
+	}
+
+	return gitDir, nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRepositoryDir(t *testing.T) {
+	t.Cleanup(func() {
+		repositoryDirOverride = ""
+	})
+
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "--quiet", repoDir).Run())
+	require.NoError(t, os.Mkdir(filepath.Join(repoDir, "src"), 0755))
+
+	// Subdirectories resolve to the repository root, like the working directory does
+	require.NoError(t, SetRepositoryDir(filepath.Join(repoDir, "src")))
+
+	foundRepoDir, err := GetRepositoryDir()
+	require.NoError(t, err)
+
+	expectedRepoDir, err := filepath.EvalSymlinks(repoDir)
+	require.NoError(t, err)
+
+	resolvedFoundRepoDir, err := filepath.EvalSymlinks(foundRepoDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedRepoDir, resolvedFoundRepoDir)
+
+	require.NoError(t, SetRepositoryDir(t.TempDir()))
+
+	_, err = GetRepositoryDir()
+	assert.ErrorContains(t, err, "is not within a Git repository")
+
+	assert.Error(t, SetRepositoryDir(filepath.Join(repoDir, "missing")))
+}
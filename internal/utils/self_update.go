@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ExtractFileFromTarGz returns the content of the file called name (at any depth) in a .tar.gz archive
+func ExtractFileFromTarGz(archive []byte, name string) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s not found in archive", name)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != name {
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("read %s from archive: %w", name, err)
+		}
+
+		return content, nil
+	}
+}
+
+// ReplaceExecutable replaces the file at executablePath with newContent.
+//
+// The new file is written next to the old one and checked with verify before it's moved into place, so that a broken
+// download never replaces a working executable. The old file is kept as a backup until the new one is in place and has
+// passed verify again, and is restored if either step fails.
+func ReplaceExecutable(executablePath string, newContent []byte, verify func(path string) error) error {
+	executableInfo, err := os.Stat(executablePath)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", executablePath, err)
+	}
+
+	// A temp file in the same directory, so that the final rename doesn't cross filesystems
+	tempFile, err := os.CreateTemp(filepath.Dir(executablePath), ".codecrafters-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath) // No-op once it's been moved into place
+
+	if _, err := tempFile.Write(newContent); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("write %s: %w", tempPath, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", tempPath, err)
+	}
+
+	if err := os.Chmod(tempPath, executableInfo.Mode().Perm()); err != nil {
+		return fmt.Errorf("chmod %s: %w", tempPath, err)
+	}
+
+	if err := verify(tempPath); err != nil {
+		return fmt.Errorf("verify new executable: %w", err)
+	}
+
+	backupPath := executablePath + ".old"
+	os.Remove(backupPath) // Left behind by a previous upgrade on Windows, see below
+
+	if err := os.Rename(executablePath, backupPath); err != nil {
+		return fmt.Errorf("back up %s: %w", executablePath, err)
+	}
+
+	if err := os.Rename(tempPath, executablePath); err != nil {
+		return rollbackExecutable(executablePath, backupPath, fmt.Errorf("move new executable into place: %w", err))
+	}
+
+	if err := verify(executablePath); err != nil {
+		return rollbackExecutable(executablePath, backupPath, fmt.Errorf("verify installed executable: %w", err))
+	}
+
+	// Windows doesn't allow removing a running executable, the backup is removed on the next upgrade instead
+	if err := os.Remove(backupPath); err != nil {
+		Logger.Debug().Err(err).Msgf("failed to remove %s", backupPath)
+	}
+
+	return nil
+}
+
+func rollbackExecutable(executablePath string, backupPath string, cause error) error {
+	if err := os.Rename(backupPath, executablePath); err != nil {
+		return fmt.Errorf("%w (restoring the previous executable from %s also failed: %s)", cause, backupPath, err)
+	}
+
+	return fmt.Errorf("%w (the previous executable was restored)", cause)
+}
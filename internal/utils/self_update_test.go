@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFileFromTarGz(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{
+		"README.md":         "readme",
+		"dist/codecrafters": "binary",
+	})
+
+	content, err := ExtractFileFromTarGz(archive, "codecrafters")
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(content))
+
+	_, err = ExtractFileFromTarGz(archive, "missing")
+	assert.ErrorContains(t, err, "missing not found in archive")
+
+	_, err = ExtractFileFromTarGz([]byte("not an archive"), "codecrafters")
+	assert.Error(t, err)
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	acceptAll := func(path string) error { return nil }
+
+	t.Run("replaces the executable", func(t *testing.T) {
+		executablePath := writeExecutable(t, "old")
+
+		require.NoError(t, ReplaceExecutable(executablePath, []byte("new"), acceptAll))
+
+		assertFileContent(t, executablePath, "new")
+		assert.NoFileExists(t, executablePath+".old")
+
+		info, err := os.Stat(executablePath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+		assertNoTempFiles(t, filepath.Dir(executablePath))
+	})
+
+	t.Run("keeps the executable if the new one fails verification", func(t *testing.T) {
+		executablePath := writeExecutable(t, "old")
+
+		err := ReplaceExecutable(executablePath, []byte("broken"), func(path string) error {
+			return fmt.Errorf("exec format error")
+		})
+
+		assert.ErrorContains(t, err, "exec format error")
+		assertFileContent(t, executablePath, "old")
+		assertNoTempFiles(t, filepath.Dir(executablePath))
+	})
+
+	t.Run("rolls back if the installed executable fails verification", func(t *testing.T) {
+		executablePath := writeExecutable(t, "old")
+
+		err := ReplaceExecutable(executablePath, []byte("new"), func(path string) error {
+			if path == executablePath {
+				return fmt.Errorf("broken install")
+			}
+
+			return nil
+		})
+
+		assert.ErrorContains(t, err, "the previous executable was restored")
+		assertFileContent(t, executablePath, "old")
+		assert.NoFileExists(t, executablePath+".old")
+	})
+}
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return buffer.Bytes()
+}
+
+func writeExecutable(t *testing.T, content string) string {
+	executablePath := filepath.Join(t.TempDir(), "codecrafters")
+	require.NoError(t, os.WriteFile(executablePath, []byte(content), 0755))
+
+	return executablePath
+}
+
+func assertFileContent(t *testing.T, path string, expectedContent string) {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expectedContent, string(content))
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	matches, err := filepath.Glob(filepath.Join(dir, ".codecrafters-upgrade-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}